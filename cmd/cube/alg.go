@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/BattlefieldDuck/algodb/pkg"
+)
+
+// mirrorAxes maps the mirror argument of "cube alg mirror" to its axis.
+var mirrorAxes = map[string]pkg.Axis{
+	"LR": pkg.AxisX,
+	"UD": pkg.AxisY,
+	"FB": pkg.AxisZ,
+}
+
+// runAlg implements "cube alg <op> ...", printing the transformed alg.
+func runAlg(args []string) {
+	usage := "Usage: cube alg invert <alg>\n" +
+		"       cube alg mirror <LR|FB|UD> <alg>\n" +
+		"       cube alg rotate <rotations> <alg>\n" +
+		"       cube alg simplify <alg>\n" +
+		"       cube alg analyze <size> <alg>\n" +
+		"       cube alg gap <size> <alg>..."
+	if len(args) < 2 {
+		log.Fatal(usage)
+	}
+
+	op := args[0]
+	parse := func(s string) pkg.Alg {
+		alg, err := pkg.ParseAlg(s)
+		if err != nil {
+			log.Fatalf("Invalid alg %q: %v", s, err)
+		}
+		return alg
+	}
+
+	size := func(s string) int {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 2 {
+			log.Fatalf("Invalid cube size %q", s)
+		}
+		return n
+	}
+
+	switch {
+	case op == "invert" && len(args) == 2:
+		fmt.Println(parse(args[1]).Invert())
+
+	case op == "simplify" && len(args) == 2:
+		fmt.Println(parse(args[1]).Simplify())
+
+	case op == "mirror" && len(args) == 3:
+		axis, ok := mirrorAxes[strings.ToUpper(args[1])]
+		if !ok {
+			log.Fatalf("Invalid mirror %q: expected LR, FB or UD", args[1])
+		}
+		fmt.Println(parse(args[2]).Mirror(axis))
+
+	case op == "rotate" && len(args) == 3:
+		rots := parse(args[1])
+		alg := parse(args[2])
+		// r1 r2 A r2' r1' is A rotated by r2, then by r1
+		for i := len(rots) - 1; i >= 0; i-- {
+			if !rots[i].IsRotation() {
+				log.Fatalf("Invalid rotation %q: expected x, y or z moves", rots[i])
+			}
+			alg = alg.Rotate(rots[i])
+		}
+		fmt.Println(alg)
+
+	case op == "analyze" && len(args) == 3:
+		a, err := pkg.Analyze(parse(args[2]), size(args[1]))
+		if err != nil {
+			log.Fatalf("Cannot analyze %q: %v", args[2], err)
+		}
+		fmt.Println(a)
+
+	case op == "gap" && len(args) >= 3:
+		n := size(args[1])
+		var gens []pkg.Alg
+		for _, s := range args[2:] {
+			gen := parse(s)
+			if err := gen.Validate(n); err != nil {
+				log.Fatalf("Invalid alg %q: %v", s, err)
+			}
+			gens = append(gens, gen)
+		}
+		fmt.Print(pkg.GAPGroup(n, gens))
+
+	default:
+		log.Fatal(usage)
+	}
+}
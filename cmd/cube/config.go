@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BattlefieldDuck/algodb/pkg"
+)
+
+// configShape returns the base name of a config file and the puzzle size its
+// name starts with, footprint first. Sizes are written with x, as in
+// "10x10x10-Centers" or "2x2x3-F2L" (a 2x2x3 cuboid, three layers high), or
+// as three digits for puzzles whose sides are all below 10: "222-CLL" is a
+// 2x2x2 cube and "223-F2L" the 2x2x3. A single number names a cube.
+func configShape(path string) (string, [3]int, error) {
+	name := configName(path)
+	prefix := configPrefix(name)
+	var dims [3]int
+	parts := strings.Split(prefix, "x")
+	switch {
+	case len(parts) == 1 && len(prefix) == 3:
+		parts = strings.Split(prefix, "")
+	case len(parts) == 1:
+		parts = []string{prefix, prefix, prefix}
+	}
+	if len(parts) != 3 {
+		return "", dims, fmt.Errorf("cannot parse puzzle size from %s: want NxMxK, three digits or N", prefix)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 1 {
+			return "", dims, fmt.Errorf("cannot parse puzzle size from %s", prefix)
+		}
+		dims[i] = n
+	}
+	return name, dims, nil
+}
+
+// configName returns the base name of a config file without its extension.
+func configName(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// configPrefix returns the part of a config name before the first dash.
+func configPrefix(name string) string { return strings.Split(name, "-")[0] }
+
+// configSize is configShape for configs of n×n cubes.
+func configSize(path string) (string, int, error) {
+	name, dims, err := configShape(path)
+	if err != nil {
+		return "", 0, err
+	}
+	if dims[0] != dims[1] || dims[1] != dims[2] {
+		return "", 0, fmt.Errorf("%s is a cuboid config, want a cube", name)
+	}
+	return name, dims[0], nil
+}
+
+// configCase is one row of a config CSV.
+type configCase struct {
+	ID       string
+	Scramble string
+	Mask     string // optional partial goal, see pkg.ParseMask
+	Goal     string // optional goal expression, see pkg.CompileGoal
+	Bandage  string // optional fused blocks, see pkg.ParseBandage
+	Notation string // optional move notation, see configNotation
+}
+
+// readConfig reads every case of a config CSV. The header row names the
+// columns: id and scramble are required, mask, goal, bandage and notation
+// are optional.
+func readConfig(path string) ([]configCase, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1 // optional columns may be left off
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header row")
+	}
+	cols := make(map[string]int)
+	for i, name := range records[0] {
+		cols[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"id", "scramble"} {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("missing %s column", name)
+		}
+	}
+	field := func(rec []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(rec) {
+			return rec[i]
+		}
+		return ""
+	}
+
+	var cases []configCase
+	for _, rec := range records[1:] {
+		if len(rec) < 2 {
+			continue
+		}
+		cases = append(cases, configCase{
+			ID:       field(rec, "id"),
+			Scramble: field(rec, "scramble"),
+			Mask:     field(rec, "mask"),
+			Goal:     field(rec, "goal"),
+			Bandage:  field(rec, "bandage"),
+			Notation: field(rec, "notation"),
+		})
+	}
+	return cases, nil
+}
+
+// configNotation returns the move notation the scrambles of a config are
+// written in: its notation column, which every row giving one must agree
+// on, or else the -notation flag, which must then agree with the column
+// too. It is empty for the puzzle's default.
+func configNotation(path, flagValue string) (string, error) {
+	cases, err := readConfig(path)
+	if err != nil {
+		return "", err
+	}
+	notation := flagValue
+	for _, cc := range cases {
+		if cc.Notation == "" || cc.Notation == notation {
+			continue
+		}
+		if notation != "" {
+			return "", fmt.Errorf("notation %q of %s conflicts with %q", cc.Notation, cc.ID, notation)
+		}
+		notation = cc.Notation
+	}
+	return notation, nil
+}
+
+// scrambledCube applies a case scramble to a solved n×n cube and checks
+// that the result is a reachable state.
+func scrambledCube(n int, cc configCase) (*pkg.Cube, error) {
+	c := pkg.NewCube(n)
+	if err := c.Moves(cc.Scramble); err != nil {
+		return nil, fmt.Errorf("invalid scramble for %s %q: %w", cc.ID, cc.Scramble, err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("scramble for %s %q: %w", cc.ID, cc.Scramble, err)
+	}
+	return c, nil
+}
+
+// caseMask parses the mask of a case, or returns nil if it has none.
+func caseMask(n int, cc configCase) (*pkg.Mask, error) {
+	if cc.Mask == "" {
+		return nil, nil
+	}
+	m, err := pkg.ParseMask(cc.Mask)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mask for %s: %w", cc.ID, err)
+	}
+	if m.Size != n {
+		return nil, fmt.Errorf("mask for %s is for a %dx%d cube, want %dx%d", cc.ID, m.Size, m.Size, n, n)
+	}
+	return m, nil
+}
+
+// caseGoal combines the mask of a case with a goal expression, taken from
+// the -goal flag if set and from the case otherwise. It returns nil if the
+// case has neither, meaning a fully solved cube.
+func caseGoal(n int, cc configCase, expr string) (pkg.CheckFunc, string, error) {
+	m, err := caseMask(n, cc)
+	if err != nil {
+		return nil, "", err
+	}
+	if expr == "" {
+		expr = cc.Goal
+	}
+	var checks []pkg.CheckFunc
+	var desc []string
+	if m != nil {
+		checks = append(checks, m.Matches)
+		desc = append(desc, "mask "+m.String())
+	}
+	if expr != "" {
+		check, err := pkg.CompileGoal(expr, n)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid goal for %s %q: %w", cc.ID, expr, err)
+		}
+		checks = append(checks, check)
+		desc = append(desc, expr)
+	}
+
+	switch len(checks) {
+	case 0:
+		return nil, "", nil
+	case 1:
+		return checks[0], desc[0], nil
+	}
+	return func(c *pkg.Cube) bool { return checks[0](c) && checks[1](c) }, strings.Join(desc, " && "), nil
+}
+
+// caseBandage parses the bandage of a case, checks that its scramble only
+// makes moves the bandage allows, and returns the bandage as the scramble
+// leaves it. It returns nil if the case has no bandage.
+func caseBandage(n int, cc configCase) (*pkg.Bandage, error) {
+	if cc.Bandage == "" {
+		return nil, nil
+	}
+	b, err := pkg.ParseBandage(cc.Bandage, n)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bandage for %s: %w", cc.ID, err)
+	}
+	alg, err := pkg.ParseAlg(cc.Scramble)
+	if err == nil {
+		err = b.Check(alg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scramble for %s %q: %w", cc.ID, cc.Scramble, err)
+	}
+	for _, m := range alg {
+		b.Apply(m)
+	}
+	return b, nil
+}
+
+// importState parses a facelet state string for an n×n cube and checks
+// that it is a reachable state.
+func importState(n int, state string) (*pkg.Cube, error) {
+	c, err := pkg.ParseFacelets(state)
+	if err != nil {
+		return nil, err
+	}
+	if c.Size != n {
+		return nil, fmt.Errorf("state is a %dx%d cube, want %dx%d", c.Size, c.Size, n, n)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
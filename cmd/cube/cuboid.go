@@ -0,0 +1,13 @@
+package main
+
+import (
+	"github.com/BattlefieldDuck/algodb/pkg"
+)
+
+// runCuboid is the solve command for cuboid configs such as 223-*.csv,
+// where dims is the footprint and height. Cases are searched for a fully
+// solved cuboid.
+func runCuboid(name string, dims [3]int, configPath, targetID string, maxDepth int, movesArg string) {
+	newCuboid := func() *pkg.Cuboid { return pkg.NewCuboid(dims[0], dims[2], dims[1]) }
+	runPuzzle(name, newCuboid().String()+" Cuboid", newCuboid, configPath, targetID, maxDepth, movesArg)
+}
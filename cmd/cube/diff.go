@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/BattlefieldDuck/algodb/pkg"
+)
+
+// runDiff implements "cube diff <config.csv> <id> <alg>" and "cube diff
+// <config.csv> <id> <db.csv> <row>": it compares the state a config case
+// sets up with the case an alg solves, i.e. the inverse of the alg applied
+// to a solved cube. A DB row is played as its pre-AUF, prefix, algorithm
+// and post-AUF. On the 2x2, where DB rows may write a leading U as a y
+// prefix, an alg that leaves the case solved after a U turn or in another
+// orientation gets that U turn and rotation added and printed with it, so
+// such rows show no differences; on other cubes they are differences.
+func runDiff(args []string) {
+	if len(args) != 3 && len(args) != 4 {
+		log.Fatal("Usage: cube diff <config.csv> <id> <alg | db.csv row>")
+	}
+	configPath, targetID := args[0], args[1]
+
+	_, n, err := configSize(configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cases, err := readConfig(configPath)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", configPath, err)
+	}
+	var want *pkg.Cube
+	for _, cc := range cases {
+		if cc.ID == targetID {
+			if want, err = scrambledCube(n, cc); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	if want == nil {
+		log.Fatalf("ID %s not found in %s", targetID, configPath)
+	}
+
+	algArg := args[2]
+	if len(args) == 4 {
+		row, err := strconv.Atoi(args[3])
+		if err != nil {
+			log.Fatalf("Invalid row %q: %v", args[3], err)
+		}
+		if algArg, err = readDBRow(args[2], row); err != nil {
+			log.Fatalf("Error reading %s: %v", args[2], err)
+		}
+	}
+	alg, err := pkg.ParseAlg(algArg)
+	if err == nil {
+		err = alg.Validate(n)
+	}
+	if err != nil {
+		log.Fatalf("Invalid alg %q: %v", algArg, err)
+	}
+
+	// only the 2x2 has no centers to tell a leading U from a y rotation, so
+	// only there is an alg finished by the U turn and rotation it needs
+	if n == 2 {
+		end := want.Copy()
+		alg.Apply(end)
+		if post, ok := end.AUF(pkg.SolvedIn()); ok {
+			post.Apply(end)
+			rot, _ := end.SolvedOrientation()
+			alg = append(append(alg, post...), rot...)
+		}
+	}
+	got := pkg.NewCube(n)
+	alg.Invert().Apply(got)
+
+	fmt.Printf("\nCase %s\n\n", targetID)
+	want.DisplayColorANSI()
+	fmt.Printf("\nCase solved by %s\n\n", alg)
+	got.DisplayColorANSI()
+	fmt.Printf("\nDifferences\n\n")
+	got.DisplayDiffANSI(want)
+
+	d, _ := got.Diff(want)
+	fmt.Printf("\n%s\n", d)
+}
+
+// readDBRow returns the moves of a row of a DB CSV, counting rows from 1
+// after the header: its pre_auf, prefix, algorithm and post_auf, in the
+// order they are played. Columns a DB leaves off count as empty.
+func readDBRow(path string, row int) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return "", err
+	}
+	if len(records) == 0 {
+		return "", fmt.Errorf("missing header row")
+	}
+	cols := make(map[string]int)
+	for i, name := range records[0] {
+		cols[strings.TrimSpace(name)] = i
+	}
+	if _, ok := cols["algorithm"]; !ok {
+		return "", fmt.Errorf("missing algorithm column")
+	}
+	if row < 1 || row >= len(records) {
+		return "", fmt.Errorf("row %d out of range 1-%d", row, len(records)-1)
+	}
+	rec := records[row]
+	var parts []string
+	for _, name := range []string{"pre_auf", "prefix", "algorithm", "post_auf"} {
+		if i, ok := cols[name]; ok && i < len(rec) && rec[i] != "" {
+			parts = append(parts, rec[i])
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
@@ -1,12 +1,11 @@
 package main
 
 import (
-	"encoding/csv"
+	"flag"
 	"fmt"
 	"log"
 	"math"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -19,75 +18,188 @@ import (
 // isSolved wraps your cube’s solved‐state check.
 func isSolved(c *pkg.Cube) bool { return c.IsSolved() }
 
+// orientationGoals maps the -orientation flag to the solved check it selects.
+var orientationGoals = map[string]pkg.CheckFunc{
+	"fixed": isSolved,
+	"y":     pkg.SolvedIn(pkg.CubeRotations[:4]...),
+	"any":   pkg.SolvedIn(),
+}
+
 func main() {
-	// Expect exactly 5 args: program, config, id, depth, moves
-	if len(os.Args) != 5 {
-		log.Fatalf("Usage: %s <config.csv> <id> <maxDepth> <move_set>\n", os.Args[0])
+	if len(os.Args) > 1 && os.Args[1] == "alg" {
+		runAlg(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		runDiff(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "skewb" {
+		runSkewb(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "sq1" {
+		runSquare1(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "scramble" {
+		runScramble(os.Args[2:])
+		return
 	}
-	configPath := os.Args[1]
-	targetID := os.Args[2]
-	depthArg := os.Args[3]
-	movesArg := os.Args[4]
+
+	orientation := flag.String("orientation", "fixed", "accepted final orientations: fixed, y or any")
+	auf := flag.Bool("auf", false, "try every pre-AUF and accept any post-AUF")
+	goalExpr := flag.String("goal", "", "goal expression, e.g. \"layer(D) solved && oriented(U)\"")
+	notation := flag.String("notation", "", "move notation of the Skewb: wca (default) or sarah, unless the config names one")
+	centers := flag.Bool("centers", false, "track every sticker and require the centers home and upright")
+	flag.Parse()
+	check, ok := orientationGoals[*orientation]
+	if !ok {
+		log.Fatalf("Invalid orientation %q: want fixed, y or any", *orientation)
+	}
+
+	// Expect exactly 4 args: config, id (or facelet state), depth, moves
+	args := flag.Args()
+	if len(args) != 4 {
+		log.Fatalf("Usage: %s [-orientation fixed|y|any] [-auf] [-goal expr] [-centers] [-notation wca|sarah] <config.csv> <id|state> <maxDepth> <move_set>\n", os.Args[0])
+	}
+	configPath := args[0]
+	targetID := args[1]
+	depthArg := args[2]
+	movesArg := args[3]
 
 	// Parse maxDepth and moves from CLI
 	maxDepth, err := strconv.Atoi(depthArg)
 	if err != nil {
 		log.Fatalf("Invalid maxDepth %q: %v", depthArg, err)
 	}
-	moves := strings.Fields(movesArg)
+	// The config may name the notation its scrambles are written in
+	moveNotation, err := configNotation(configPath, *notation)
+	if err != nil {
+		log.Fatalf("%s: %v", configPath, err)
+	}
+
+	// Puzzles other than cubes and cuboids have their own solve command
+	name := configName(configPath)
+	if run, ok := puzzleConfigs[configPrefix(name)]; ok {
+		if *orientation != "fixed" || *auf || *goalExpr != "" || *centers {
+			log.Fatal("-orientation, -auf, -goal and -centers are only supported for cubes")
+		}
+		run(name, moveNotation, configPath, targetID, maxDepth, movesArg)
+		return
+	}
+
+	if moveNotation != "" {
+		log.Fatal("-notation and the notation column are only supported for the Skewb")
+	}
 
 	// Derive cube size (n) and config base name
-	base := filepath.Base(configPath)
-	name := strings.TrimSuffix(base, filepath.Ext(base))
-	parts := strings.Split(name, "-")
-	sizeChar := parts[0][0]
-	n, err := strconv.Atoi(string(sizeChar))
+	name, dims, err := configShape(configPath)
 	if err != nil {
-		log.Fatalf("Cannot parse cube size from %s: %v", parts[0], err)
+		log.Fatal(err)
+	}
+	if dims[0] != dims[1] || dims[1] != dims[2] {
+		if *orientation != "fixed" || *auf || *goalExpr != "" || *centers {
+			log.Fatal("-orientation, -auf, -goal and -centers are not supported for cuboids")
+		}
+		runCuboid(name, dims, configPath, targetID, maxDepth, movesArg)
+		return
 	}
+	n := dims[0]
 
-	// Read CSV for scramble
-	f, err := os.Open(configPath)
+	// Reject bad move sets before doing any work
+	moves, err := pkg.ParseMoveSet(strings.Fields(movesArg), n)
 	if err != nil {
-		log.Fatalf("Error opening %s: %v", configPath, err)
+		log.Fatalf("Invalid move set %q: %v", movesArg, err)
 	}
-	defer f.Close()
-	reader := csv.NewReader(f)
-	records, err := reader.ReadAll()
+
+	// Read the config and check every scramble in it
+	cases, err := readConfig(configPath)
 	if err != nil {
-		log.Fatalf("Error reading CSV: %v", err)
+		log.Fatalf("Error reading %s: %v", configPath, err)
 	}
-
-	// Find scramble by ID
+	var c *pkg.Cube
 	var scramble string
-	found := false
-	for i, rec := range records {
-		if i == 0 {
-			continue // header
+	imported := false
+	var goal pkg.CheckFunc
+	var goalDesc, goalExprs, bandageDesc string
+	var bandage *pkg.Bandage
+	for _, cc := range cases {
+		cube, err := scrambledCube(n, cc)
+		if err != nil {
+			log.Fatalf("%s: %v", configPath, err)
+		}
+		g, desc, err := caseGoal(n, cc, *goalExpr)
+		if err != nil {
+			log.Fatalf("%s: %v", configPath, err)
 		}
-		if len(rec) < 2 {
-			continue
+		b, err := caseBandage(n, cc)
+		if err != nil {
+			log.Fatalf("%s: %v", configPath, err)
 		}
-		if rec[0] == targetID {
-			scramble = rec[1]
-			found = true
-			break
+		if cc.ID == targetID {
+			c, scramble, goal, goalDesc, goalExprs = cube, cc.Scramble, g, desc, cc.Goal
+			bandage, bandageDesc = b, cc.Bandage
 		}
 	}
-	if !found {
-		log.Fatalf("ID %s not found in %s", targetID, configPath)
+	if c == nil {
+		// not a config ID, so it must be a facelet state string
+		if c, err = importState(n, targetID); err != nil {
+			log.Fatalf("ID %s not found in %s and not a state: %v", targetID, configPath, err)
+		}
+		scramble, imported = "state "+targetID, true
+		if goal, goalDesc, err = caseGoal(n, configCase{ID: targetID}, *goalExpr); err != nil {
+			log.Fatalf("%v", err)
+		}
 	}
 
-	// Create and scramble the cube
-	c := pkg.NewCube(n)
-	if err := c.Moves(scramble); err != nil {
-		log.Fatalf("Error scrambling cube: %v", err)
+	// A case mask or goal replaces the solved check with its partial goal
+	if goal != nil {
+		if *orientation != "fixed" {
+			log.Fatalf("-orientation %s cannot be combined with the goal of %s", *orientation, targetID)
+		}
+		check = goal
+	}
+
+	// With -centers, or a goal testing centers_oriented, the scramble is
+	// replayed on a cube tracking its stickers, so that its center turns
+	// count; an imported state is taken as oriented
+	if *goalExpr != "" {
+		goalExprs = *goalExpr
+	}
+	if *centers || pkg.GoalTracksStickers(goalExprs) {
+		if *orientation != "fixed" {
+			log.Fatalf("-centers cannot be combined with -orientation %s", *orientation)
+		}
+		if imported {
+			c.TrackOrientation()
+		} else {
+			c = pkg.NewCube(n)
+			c.TrackOrientation()
+			c.Moves(scramble)
+		}
+	}
+	if *centers {
+		base := check
+		check = func(c *pkg.Cube) bool { return base(c) && c.CentersOriented() }
 	}
 
 	// Display cube state
 	pkg.Printf("ID: %s\n", targetID)
 	pkg.Printf("MaxDepth: %d\n", maxDepth)
 	pkg.Printf("MoveSet: %s\n", movesArg)
+	pkg.Printf("Orientation: %s\n", *orientation)
+	pkg.Printf("AUF: %t\n", *auf)
+	pkg.Printf("Centers: %t\n", *centers)
+	if goal != nil {
+		pkg.Printf("Goal: %s\n", goalDesc)
+	}
+	if bandage != nil {
+		if *auf {
+			log.Fatal("-auf is not supported for bandaged cases")
+		}
+		pkg.Printf("Bandage: %s\n", bandageDesc)
+	}
 
 	fmt.Printf("\n%dx%dx%d Cube - %s\n\n", n, n, n, scramble)
 	c.DisplayColorANSI()
@@ -96,18 +208,32 @@ func main() {
 	c.DisplayColorANSIUFace()
 	fmt.Println()
 
-	// Compute branching parameters
-	faceSet := make(map[rune]struct{})
+	// Compute branching parameters: the search never makes two moves of a
+	// group in a row, so each move is followed by the moves of other groups
+	groupSize := make(map[int]int)
 	for _, m := range moves {
-		faceSet[rune(m[0])] = struct{}{}
+		groupSize[c.MoveGroup(m.Index())]++
+	}
+	followers := 0
+	for _, m := range moves {
+		followers += len(moves) - groupSize[c.MoveGroup(m.Index())]
+	}
+	branchingFactor := float64(followers) / float64(len(moves))
+
+	// With -auf, search once per pre-AUF and ignore the final AUF
+	preAUFs, target := pkg.AUFs[:1], check
+	if *auf {
+		preAUFs, target = pkg.AUFs[:], pkg.AnyAUF(check)
 	}
-	distinctFaces := len(faceSet)
-	branchingFactor := len(moves) - distinctFaces
 
-	// Compute total DFS nodes
-	total := len(moves) *
-		(int(math.Pow(float64(branchingFactor), float64(maxDepth))) - 1) /
-		(branchingFactor - 1)
+	// Compute total DFS nodes: len(moves) at depth 1, then branchingFactor
+	// times as many at each further depth
+	nodes, level := 0.0, float64(len(moves))
+	for range maxDepth {
+		nodes += level
+		level *= branchingFactor
+	}
+	total := int(min(float64(len(preAUFs))*nodes, math.MaxInt64/2))
 
 	pkg.Printf("Total nodes to explore: %d\n", total)
 
@@ -134,7 +260,34 @@ func main() {
 	start := time.Now()
 
 	// Run parallel solver
-	solutions := pkg.FindSolutionsParallelDFS(c, moves, isSolved, maxDepth, nil)
+	var solutions []internal.Solution
+	for _, pre := range preAUFs {
+		initial := c.Copy()
+		pre.Apply(initial)
+		var found []pkg.Alg
+		if bandage != nil {
+			// the search skips moves the fused blocks do not allow
+			found = pkg.FindBandagedAlgs(&pkg.BandagedCube{Cube: initial, Bandage: bandage}, moves, target, maxDepth, nil)
+		} else {
+			found = pkg.FindAlgsParallelDFS(initial, moves, target, maxDepth, nil)
+		}
+		for _, sol := range found {
+			// an alg starting or ending in a U turn repeats one from another AUF
+			if *auf && (sol[0].IsAUF() || sol[len(sol)-1].IsAUF()) {
+				continue
+			}
+
+			// Record the post-AUF, then the rotation the solution ends in
+			end := initial.Copy()
+			sol.Apply(end)
+			post, _ := end.AUF(check)
+			post.Apply(end)
+			if rot, ok := end.SolvedOrientation(); ok {
+				sol = append(sol, rot...)
+			}
+			solutions = append(solutions, internal.Solution{PreAUF: pre, Alg: sol, PostAUF: post})
+		}
+	}
 
 	// Measure elapsed time and throughput
 	elapsed := time.Since(start)
@@ -145,9 +298,29 @@ func main() {
 	// Print solutions
 	pkg.Printf("Found %d solution(s):\n\n", len(solutions))
 	for i, sol := range solutions {
-		fmt.Printf("%2d [%d]: %s\n", i+1, len(sol), strings.Join(sol, " "))
+		fmt.Printf("%2d [%d]: %s\n", i+1, len(sol.Alg), formatSolution(sol))
 	}
 	fmt.Println()
 
-	internal.CreateAlgorithms(name, targetID, solutions)
+	// An imported state is not a DB case, so its solutions are only printed
+	if imported {
+		pkg.Printf("Imported state: not written to db/%s\n", name)
+		return
+	}
+	// only the 2x2 has no centers to tell a leading U from a y rotation
+	if err := internal.WriteSolutions(name, targetID, solutions, n == 2); err != nil {
+		log.Fatalf("Error writing algorithms: %v", err)
+	}
+}
+
+// formatSolution prints a solution with its AUFs in parentheses.
+func formatSolution(sol internal.Solution) string {
+	s := sol.Alg.String()
+	if len(sol.PreAUF) > 0 {
+		s = "(" + sol.PreAUF.String() + ") " + s
+	}
+	if len(sol.PostAUF) > 0 {
+		s += " (" + sol.PostAUF.String() + ")"
+	}
+	return s
 }
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/BattlefieldDuck/algodb/internal"
+	"github.com/BattlefieldDuck/algodb/pkg"
+)
+
+// searchPuzzle is a puzzle the solve command can search besides the cube:
+// it parses its own move sets and scrambles and prints its net.
+type searchPuzzle[P any] interface {
+	pkg.Puzzle[P]
+	ParseMoves(s string) ([]int, error)
+	DisplayColorANSI()
+}
+
+// maskedPuzzle is a searchPuzzle whose configs may carry a mask column,
+// parsed into the goal of the search.
+type maskedPuzzle[P any] interface {
+	ParseMask(s string) (func(P) bool, error)
+}
+
+// puzzleConfigs maps the name prefix of configs for puzzles other than
+// cubes and cuboids to their solve command. notation is the -notation flag
+// or the config's notation column, empty for the puzzle's default.
+var puzzleConfigs = map[string]func(name, notation, configPath, targetID string, maxDepth int, movesArg string){
+	"pyra": func(name, notation, configPath, targetID string, maxDepth int, movesArg string) {
+		if notation != "" {
+			log.Fatal("-notation and the notation column are not supported for the Pyraminx")
+		}
+		runPuzzle(name, "Pyraminx", pkg.NewPyraminx, configPath, targetID, maxDepth, movesArg)
+	},
+	"skewb": func(name, notation, configPath, targetID string, maxDepth int, movesArg string) {
+		n, ok := skewbNotations[notation]
+		if !ok {
+			log.Fatalf("Invalid notation %q: want wca or sarah", notation)
+		}
+		newSkewb := func() *pkg.Skewb { return pkg.NewSkewb(n) }
+		runPuzzle(name, "Skewb", newSkewb, configPath, targetID, maxDepth, movesArg)
+	},
+	"mega": func(name, notation, configPath, targetID string, maxDepth int, movesArg string) {
+		if notation != "" {
+			log.Fatal("-notation and the notation column are not supported for the Megaminx")
+		}
+		runPuzzle(name, "Megaminx", pkg.NewMegaminx, configPath, targetID, maxDepth, movesArg)
+	},
+}
+
+// skewbNotations maps the -notation flag to a Skewb notation.
+var skewbNotations = map[string]pkg.SkewbNotation{
+	"":      pkg.SkewbWCA,
+	"wca":   pkg.SkewbWCA,
+	"sarah": pkg.SkewbSarah,
+}
+
+// runPuzzle is the solve command for a searchPuzzle: every case of the
+// config is scrambled from newPuzzle and the target is searched for a fully
+// solved state, or the state its mask matches if the puzzle is a
+// maskedPuzzle. title names the puzzle in the output.
+func runPuzzle[P searchPuzzle[P]](name, title string, newPuzzle func() P, configPath, targetID string, maxDepth int, movesArg string) {
+	// Reject bad move sets before doing any work
+	moves, err := newPuzzle().ParseMoves(movesArg)
+	if err != nil {
+		log.Fatalf("Invalid move set %q: %v", movesArg, err)
+	}
+
+	// Read the config and check every scramble in it
+	cases, err := readConfig(configPath)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", configPath, err)
+	}
+	var p P
+	var scramble string
+	check := P.IsSolved
+	found := false
+	for _, cc := range cases {
+		if cc.Goal != "" || cc.Bandage != "" {
+			log.Fatalf("%s: goals and bandages are not supported for %s", configPath, title)
+		}
+		q := newPuzzle()
+		goal := P.IsSolved
+		if cc.Mask != "" {
+			mp, ok := any(q).(maskedPuzzle[P])
+			if !ok {
+				log.Fatalf("%s: masks are not supported for %s", configPath, title)
+			}
+			if goal, err = mp.ParseMask(cc.Mask); err != nil {
+				log.Fatalf("%s: invalid mask for %s: %v", configPath, cc.ID, err)
+			}
+		}
+		seq, err := q.ParseMoves(cc.Scramble)
+		if err != nil {
+			log.Fatalf("%s: invalid scramble for %s %q: %v", configPath, cc.ID, cc.Scramble, err)
+		}
+		for _, m := range seq {
+			q.Apply(m)
+		}
+		if cc.ID == targetID {
+			p, scramble, check, found = q, cc.Scramble, goal, true
+		}
+	}
+	if !found {
+		log.Fatalf("ID %s not found in %s", targetID, configPath)
+	}
+
+	// Display puzzle state
+	pkg.Printf("ID: %s\n", targetID)
+	pkg.Printf("MaxDepth: %d\n", maxDepth)
+	pkg.Printf("MoveSet: %s\n", movesArg)
+
+	fmt.Printf("\n%s - %s\n\n", title, scramble)
+	p.DisplayColorANSI()
+	fmt.Println()
+
+	start := time.Now()
+	solutions := pkg.FindPuzzleAlgs(p, moves, check, maxDepth, nil)
+	pkg.Printf("Elapsed time: %s\n", time.Since(start))
+
+	// Print solutions
+	records := internal.PuzzleRecords(p, solutions)
+	pkg.Printf("Found %d solution(s):\n\n", len(records))
+	for i, r := range records {
+		fmt.Printf("%2d [%d]: %s\n", i+1, r.Length, r.Alg)
+	}
+	fmt.Println()
+
+	if err := internal.WriteRecords(name, targetID, records); err != nil {
+		log.Fatalf("Error writing algorithms: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/BattlefieldDuck/algodb/pkg"
+)
+
+// runScramble implements "cube scramble [-size N] [-count K] [-seed S]
+// [-config path -case ID]": it prints random-state scrambles of a 2x2 or
+// 3x3. With -config and -case every scramble sets up that case after a
+// random pre-AUF, which is printed with it: doing the pre-AUF leaves the
+// case as the config defines it, held as pkg.HeldHome holds it.
+func runScramble(args []string) {
+	fs := flag.NewFlagSet("scramble", flag.ExitOnError)
+	size := fs.Int("size", 3, "cube size: 2 or 3")
+	count := fs.Int("count", 1, "number of scrambles")
+	seed := fs.Int64("seed", 0, "random seed (default: the current time)")
+	configPath := fs.String("config", "", "config CSV holding the case")
+	caseID := fs.String("case", "", "ID of the case to scramble into")
+	fs.Parse(args)
+	if fs.NArg() != 0 || *count < 1 || (*configPath == "") != (*caseID == "") {
+		log.Fatal("Usage: cube scramble [-size 2|3] [-count K] [-seed S] [-config <config.csv> -case <id>]")
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+
+	// the case to scramble into, if any; its config decides the size
+	var target *pkg.Cube
+	if *configPath != "" {
+		_, n, err := configSize(*configPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cases, err := readConfig(*configPath)
+		if err != nil {
+			log.Fatalf("Error reading %s: %v", *configPath, err)
+		}
+		for _, cc := range cases {
+			if cc.ID == *caseID {
+				if target, err = scrambledCube(n, cc); err != nil {
+					log.Fatalf("%s: %v", *configPath, err)
+				}
+			}
+		}
+		if target == nil {
+			log.Fatalf("ID %s not found in %s", *caseID, *configPath)
+		}
+		*size = n
+	}
+
+	solver, err := pkg.NewSolver(*size)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// scrambles do not rotate the cube, so the pre-AUF turns the U face of
+	// the case held home
+	if target != nil {
+		if target, err = pkg.HeldHome(target); err != nil {
+			log.Fatalf("Error scrambling %s: %v", *caseID, err)
+		}
+	}
+	for i := 1; i <= *count; i++ {
+		if target == nil {
+			scramble, err := pkg.RandomScramble(*size, solver, rng)
+			if err != nil {
+				log.Fatalf("Error scrambling: %v", err)
+			}
+			fmt.Printf("%d. %s\n", i, scramble)
+			continue
+		}
+
+		// the solver sees the case before its pre-AUF is done
+		auf := pkg.AUFs[rng.Intn(len(pkg.AUFs))]
+		c := target.Copy()
+		auf.Invert().Apply(c)
+		scramble, err := pkg.ScrambleTo(solver, c)
+		if err != nil {
+			log.Fatalf("Error scrambling %s: %v", *caseID, err)
+		}
+		if len(auf) == 0 {
+			fmt.Printf("%d. %s\n", i, scramble)
+		} else {
+			fmt.Printf("%d. %s  (pre-AUF %s)\n", i, scramble, auf)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/BattlefieldDuck/algodb/pkg"
+)
+
+// skewbWCAMoves is the move set the Skewb distance table is built from.
+const skewbWCAMoves = "R R' L L' U U' B B'"
+
+// runSkewb implements "cube skewb [-notation wca|sarah] <scramble>": it
+// builds the Skewb distance table and prints the optimal distance and a
+// solution of the scrambled state, in the chosen notation.
+func runSkewb(args []string) {
+	fs := flag.NewFlagSet("skewb", flag.ExitOnError)
+	notation := fs.String("notation", "wca", "move notation: wca or sarah")
+	fs.Parse(args)
+	n, ok := skewbNotations[*notation]
+	if !ok || fs.NArg() != 1 {
+		log.Fatal("Usage: cube skewb [-notation wca|sarah] <scramble>")
+	}
+
+	s := pkg.NewSkewb(n)
+	if err := s.Moves(fs.Arg(0)); err != nil {
+		log.Fatalf("Invalid scramble %q: %v", fs.Arg(0), err)
+	}
+	fmt.Printf("\nSkewb - %s\n\n", fs.Arg(0))
+	s.DisplayColorANSI()
+	fmt.Println()
+
+	wca := pkg.NewSkewb(pkg.SkewbWCA)
+	tableMoves, _ := wca.ParseMoves(skewbWCAMoves)
+	table := pkg.NewDistanceTable(wca, tableMoves, (*pkg.Skewb).Code)
+	pkg.Printf("Distance table: %d states, God's number %d\n", table.Len(), len(table.Counts)-1)
+
+	moves, _ := s.ParseMoves(strings.Join(s.TurnNames(), " "))
+	sol, _ := table.Solve(s, moves)
+	names := make([]string, len(sol))
+	for i, m := range sol {
+		names[i] = s.MoveNotation(m)
+	}
+	fmt.Printf("Optimal [%d]: %s\n", len(sol), strings.Join(names, " "))
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/BattlefieldDuck/algodb/internal"
+	"github.com/BattlefieldDuck/algodb/pkg"
+)
+
+// runSquare1 implements the Square-1 commands:
+//
+//	cube sq1 show <alg>    print the state an alg gives from solved
+//	cube sq1 cubeshape     write db/sq1-cubeshape/, one file per shape
+func runSquare1(args []string) {
+	switch {
+	case len(args) == 2 && args[0] == "show":
+		s := pkg.NewSquare1()
+		if err := s.Moves(args[1]); err != nil {
+			log.Fatalf("Invalid alg %q: %v", args[1], err)
+		}
+		top, bottom := s.Shape()
+		fmt.Printf("\nSquare-1 - %s\n\n", args[1])
+		s.DisplayColorANSI()
+		fmt.Printf("\nShape: %s %s\n", top, bottom)
+
+	case len(args) == 1 && args[0] == "cubeshape":
+		cases := pkg.CubeShapes()
+		for _, c := range cases[1:] { // the first is cube shape itself
+			id := c.Top + "-" + c.Bottom
+			fmt.Printf("%-22s [%d]: %s\n", id, c.Solution.Slashes(), c.Solution)
+			records := []internal.Record{{Length: c.Solution.Slashes(), Alg: c.Solution.String()}}
+			if err := internal.WriteRecords("sq1-cubeshape", id, records); err != nil {
+				log.Fatalf("Error writing algorithms: %v", err)
+			}
+		}
+		pkg.Printf("Wrote %d shapes\n", len(cases)-1)
+
+	default:
+		log.Fatal("Usage: cube sq1 show <alg> | cube sq1 cubeshape")
+	}
+}
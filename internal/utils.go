@@ -7,73 +7,115 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/BattlefieldDuck/algodb/pkg"
 )
 
-// CreateAlgorithms will:
-// 1. Normalize any U-layer first moves into a y-rotation.
-// 2. Sort by move-count (ignoring any x/y/z rotations) then lexicographically.
-// 3. Write out a CSV at /db/<name>/<targetID>.csv with columns: length,prefix,algorithm
-func CreateAlgorithms(name, targetID string, solutions [][]string) error {
-	type entry struct {
-		prefix   string   // the x/y/z rotation (if any)
-		algMoves []string // the face-turns only (no x/y/z)
-		fullAlg  []string // the full move list after normalization
+// Solution is a found algorithm with the U-layer adjustments it needs
+// before and after it.
+type Solution struct {
+	PreAUF  pkg.Alg
+	Alg     pkg.Alg
+	PostAUF pkg.Alg
+}
+
+// CreateAlgorithms writes 2x2 solutions that need no AUF; see
+// WriteSolutions.
+func CreateAlgorithms(name, targetID string, solutions []pkg.Alg) error {
+	list := make([]Solution, len(solutions))
+	for i, sol := range solutions {
+		list[i] = Solution{Alg: sol}
 	}
+	return WriteSolutions(name, targetID, list, true)
+}
 
-	var list []entry
+// WriteSolutions will:
+// 1. With uAsY, normalize any U-layer first moves into a y-rotation.
+// 2. Split off a leading x/y/z rotation as the prefix, keeping any ending rotation.
+// 3. Count moves in STM, ignoring any x/y/z rotations and AUFs.
+// 4. Write the records out with WriteRecords.
+//
+// uAsY is only right where a U turn and a y rotation set up the same case,
+// as on the 2x2, which has no centers to tell them apart; on a 3x3 a
+// leading U is part of the solution.
+func WriteSolutions(name, targetID string, solutions []Solution, uAsY bool) error {
+	var list []Record
 	for _, sol := range solutions {
 		// copy so we don’t clobber callers’ slice
-		moves := append([]string(nil), sol...)
+		moves := append(pkg.Alg(nil), sol.Alg...)
 
 		// 1) if first move is U, U' or U2 → turn it into a cube-rotation on y
-		if len(moves) > 0 {
-			switch moves[0] {
-			case "U":
-				moves[0] = "y"
-			case "U'":
-				moves[0] = "y'"
-			case "U2":
-				moves[0] = "y2"
-			}
+		if uAsY && len(moves) > 0 && moves[0].IsAUF() {
+			moves[0] = pkg.Move{Face: pkg.Uface, Amount: moves[0].Amount, Kind: pkg.MoveRotation}
 		}
 
-		// extract a prefix if it’s an x/y/z rotation
+		// 2) extract a prefix if it’s an x/y/z rotation; later rotations,
+		// such as the ending rotation of an orientation-free goal, stay put
 		prefix := ""
-		if len(moves) > 0 {
-			p := moves[0]
-			if strings.HasPrefix(p, "x") || strings.HasPrefix(p, "y") || strings.HasPrefix(p, "z") {
-				prefix = p
-			}
+		if len(moves) > 0 && moves[0].IsRotation() {
+			prefix = moves[0].String()
+			moves = moves[1:]
 		}
 
-		// gather only the face-turns (drop any x/y/z)
-		var faceTurns []string
-		for _, m := range moves {
-			if strings.HasPrefix(m, "x") || strings.HasPrefix(m, "y") || strings.HasPrefix(m, "z") {
-				continue
-			}
-			faceTurns = append(faceTurns, m)
-		}
-
-		list = append(list, entry{
-			prefix:   prefix,
-			algMoves: faceTurns,
-			fullAlg:  moves,
+		list = append(list, Record{
+			Length:  moves.Len(pkg.STM),
+			Prefix:  prefix,
+			Alg:     moves.String(),
+			PreAUF:  sol.PreAUF.String(),
+			PostAUF: sol.PostAUF.String(),
 		})
 	}
+	return WriteRecords(name, targetID, list)
+}
+
+// Record is one row of an algorithm DB: the length, an optional prefix
+// (a rotation or other setup to hold the puzzle with), the algorithm and
+// the adjustments it needs before and after.
+type Record struct {
+	Length  int
+	Prefix  string
+	Alg     string
+	PreAUF  string
+	PostAUF string
+}
+
+// PuzzleRecords formats solutions found by pkg.FindPuzzleAlgs on any puzzle,
+// counting every move.
+func PuzzleRecords[P pkg.Puzzle[P]](p P, solutions [][]int) []Record {
+	list := make([]Record, len(solutions))
+	for i, sol := range solutions {
+		moves := make([]string, len(sol))
+		for j, m := range sol {
+			moves[j] = p.MoveNotation(m)
+		}
+		list[i] = Record{Length: len(sol), Alg: strings.Join(moves, " ")}
+	}
+	return list
+}
+
+// WriteRecords will:
+// 1. Sort by length, then prefix, then algorithm and AUFs lexicographically.
+// 2. Write out a CSV at /db/<name>/<targetID>.csv with columns: length,prefix,algorithm,
+// followed by pre_auf,post_auf only if some record has an AUF.
+func WriteRecords(name, targetID string, records []Record) error {
+	list := append([]Record(nil), records...)
 
-	// 2) sort by length, then prefix, then moves, then by the lexicographic join
+	// 1) sort by length, then prefix, then moves, then AUFs
 	sort.Slice(list, func(i, j int) bool {
-		if len(list[i].algMoves) != len(list[j].algMoves) {
-			return len(list[i].algMoves) < len(list[j].algMoves)
+		a, b := list[i], list[j]
+		if a.Length != b.Length {
+			return a.Length < b.Length
 		}
-		if list[i].prefix != list[j].prefix {
-			return list[i].prefix < list[j].prefix
+		if a.Prefix != b.Prefix {
+			return a.Prefix < b.Prefix
 		}
-		// join here to compare the sequences
-		ai := strings.Join(list[i].algMoves, " ")
-		aj := strings.Join(list[j].algMoves, " ")
-		return ai < aj
+		if a.Alg != b.Alg {
+			return a.Alg < b.Alg
+		}
+		if a.PreAUF != b.PreAUF {
+			return a.PreAUF < b.PreAUF
+		}
+		return a.PostAUF < b.PostAUF
 	})
 
 	// ensure the output directory exists
@@ -92,20 +134,46 @@ func CreateAlgorithms(name, targetID string, solutions [][]string) error {
 	w := csv.NewWriter(f)
 	defer w.Flush()
 
+	// the AUF columns are left off DBs that need no AUFs
+	aufs := false
+	for _, e := range list {
+		if e.PreAUF != "" || e.PostAUF != "" {
+			aufs = true
+		}
+	}
+
 	// header row
-	if err := w.Write([]string{"length", "prefix", "algorithm"}); err != nil {
+	header := []string{"length", "prefix", "algorithm"}
+	if aufs {
+		header = append(header, "pre_auf", "post_auf")
+	}
+	if err := w.Write(header); err != nil {
 		return err
 	}
 
-	// 3) write each sorted entry
+	// 2) write each sorted entry
 	for _, e := range list {
-		lengthStr := strconv.Itoa(len(e.algMoves))
-		// algorithm is the trimmed, face-turn sequence
-		algStr := strings.Join(e.algMoves, " ")
-		if err := w.Write([]string{lengthStr, e.prefix, algStr}); err != nil {
+		row := []string{strconv.Itoa(e.Length), e.Prefix, e.Alg}
+		if aufs {
+			row = append(row, e.PreAUF, e.PostAUF)
+		}
+		if err := w.Write(row); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+// CreateAlgorithmsFromStrings is the string based form of CreateAlgorithms.
+func CreateAlgorithmsFromStrings(name, targetID string, solutions [][]string) error {
+	algs := make([]pkg.Alg, 0, len(solutions))
+	for _, sol := range solutions {
+		alg, err := pkg.ParseAlgStrings(sol)
+		if err != nil {
+			return err
+		}
+		algs = append(algs, alg)
+	}
+	return CreateAlgorithms(name, targetID, algs)
+}
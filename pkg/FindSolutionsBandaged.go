@@ -0,0 +1,22 @@
+package pkg
+
+// FindBandagedAlgs is FindAlgsParallelDFS for a bandaged cube: moves the
+// bandage blocks are skipped, and check is run on the cube alone.
+func FindBandagedAlgs(
+	initial *BandagedCube,
+	moves []Move,
+	check CheckFunc,
+	maxDepth int,
+	progress chan<- struct{},
+) []Alg {
+	solved := func(b *BandagedCube) bool { return check(b.Cube) }
+	found := FindPuzzleAlgs(initial, MoveIndices(moves), solved, maxDepth, progress)
+	solutions := make([]Alg, len(found))
+	for i, sol := range found {
+		solutions[i] = make(Alg, len(sol))
+		for j, m := range sol {
+			solutions[i][j] = MoveFromIndex(m)
+		}
+	}
+	return solutions
+}
@@ -0,0 +1,25 @@
+package pkg
+
+// FindCuboidAlgs is FindAlgsParallelDFS for cuboids. It fails up front if a
+// move is not legal on the cuboid, e.g. a quarter turn of a non-square
+// layer.
+func FindCuboidAlgs(
+	initial *Cuboid,
+	moves []Move,
+	check func(q *Cuboid) bool,
+	maxDepth int,
+) ([]Alg, error) {
+	idx, err := initial.MoveIndices(moves)
+	if err != nil {
+		return nil, err
+	}
+	found := FindPuzzleAlgs(initial, idx, check, maxDepth, nil)
+	solutions := make([]Alg, len(found))
+	for i, sol := range found {
+		solutions[i] = make(Alg, len(sol))
+		for j, m := range sol {
+			solutions[i][j] = initial.move(m)
+		}
+	}
+	return solutions, nil
+}
@@ -4,20 +4,22 @@ import (
 	"slices"
 )
 
-func FindSolutionsIter(
+// FindAlgsIter searches every move sequence up to maxDepth with an explicit
+// stack, replaying each path onto a fresh copy of initial.
+func FindAlgsIter(
 	initial *Cube,
-	moveSet []string,
+	moveSet []Move,
 	check CheckFunc,
 	maxDepth int,
 	progress chan<- struct{},
-) [][]string {
+) []Alg {
 	type frame struct {
-		path []string // moves so far
+		path Alg // moves so far
 	}
 
-	solutions := make([][]string, 0)
+	solutions := make([]Alg, 0)
 	// our explicit stack of frames
-	stack := []frame{{path: []string{}}}
+	stack := []frame{{path: Alg{}}}
 
 	for len(stack) > 0 {
 		// pop
@@ -26,7 +28,7 @@ func FindSolutionsIter(
 
 		for _, m := range moveSet {
 			// skip same-axis repeats
-			if len(f.path) > 0 && f.path[len(f.path)-1][0] == m[0] {
+			if len(f.path) > 0 && f.path[len(f.path)-1].sameFace(m) {
 				continue
 			}
 
@@ -35,12 +37,7 @@ func FindSolutionsIter(
 
 			// apply move onto a fresh copy
 			next := initial.Copy()
-			for _, m := range newPath {
-				if err := next.Move(m); err != nil {
-					Printf("skipping invalid move %s: %v\n", m, err)
-					continue
-				}
-			}
+			newPath.Apply(next)
 
 			// progress tick
 			if progress != nil {
@@ -61,3 +58,21 @@ func FindSolutionsIter(
 
 	return solutions
 }
+
+// FindSolutionsIter is the string based form of FindAlgsIter. It
+// searches nothing if the move set does not parse; callers that need the
+// *ParseError check the set with ParseMoveSet first.
+func FindSolutionsIter(
+	initial *Cube,
+	moveSet []string,
+	check CheckFunc,
+	maxDepth int,
+	progress chan<- struct{},
+) [][]string {
+	set, err := ParseMoveSet(moveSet, initial.Size)
+	if err != nil {
+		Printf("invalid move set: %v\n", err)
+		return nil
+	}
+	return algStrings(FindAlgsIter(initial, set, check, maxDepth, progress))
+}
@@ -4,29 +4,29 @@ import (
 	"sync"
 )
 
-// FindSolutionsParallel spawns one worker per first‐move.
-func FindSolutionsParallel(
+// FindAlgsParallel spawns one worker per first‐move.
+func FindAlgsParallel(
 	initial *Cube,
-	moves []string,
+	moves []Move,
 	check CheckFunc,
 	maxDepth int,
 	progress chan<- struct{},
-) [][]string {
+) []Alg {
 	var (
 		wg        sync.WaitGroup
 		solMu     sync.Mutex
-		solutions [][]string
+		solutions []Alg
 	)
 
 	for _, first := range moves {
 		// skip repeated‐face pruning on depth=1 if you like
 		wg.Add(1)
-		go func(firstMove string) {
+		go func(firstMove Move) {
 			defer wg.Done()
 
 			// each branch gets its own explicit stack of paths
-			type frame struct{ path []string }
-			stack := []frame{{path: []string{firstMove}}}
+			type frame struct{ path Alg }
+			stack := []frame{{path: Alg{firstMove}}}
 
 			for len(stack) > 0 {
 				// pop
@@ -35,13 +35,7 @@ func FindSolutionsParallel(
 
 				// apply this branch's current sequence
 				next := initial.Copy()
-
-				for _, m := range f.path {
-					if err := next.Move(m); err != nil {
-						Printf("skipping invalid move %s: %v\n", m, err)
-						continue
-					}
-				}
+				f.path.Apply(next)
 
 				// tick progress
 				if progress != nil {
@@ -57,13 +51,13 @@ func FindSolutionsParallel(
 
 				// push deeper children
 				if len(f.path) < maxDepth {
-					lastFace := f.path[len(f.path)-1][0]
+					last := f.path[len(f.path)-1]
 					for _, mv := range moves {
-						if mv[0] == lastFace {
+						if mv.sameFace(last) {
 							continue
 						}
 						// copy path and append
-						newPath := make([]string, len(f.path), len(f.path)+1)
+						newPath := make(Alg, len(f.path), len(f.path)+1)
 						copy(newPath, f.path)
 						newPath = append(newPath, mv)
 						stack = append(stack, frame{path: newPath})
@@ -77,3 +71,21 @@ func FindSolutionsParallel(
 
 	return solutions
 }
+
+// FindSolutionsParallel is the string based form of FindAlgsParallel. It
+// searches nothing if the move set does not parse; callers that need the
+// *ParseError check the set with ParseMoveSet first.
+func FindSolutionsParallel(
+	initial *Cube,
+	moves []string,
+	check CheckFunc,
+	maxDepth int,
+	progress chan<- struct{},
+) [][]string {
+	set, err := ParseMoveSet(moves, initial.Size)
+	if err != nil {
+		Printf("invalid move set: %v\n", err)
+		return nil
+	}
+	return algStrings(FindAlgsParallel(initial, set, check, maxDepth, progress))
+}
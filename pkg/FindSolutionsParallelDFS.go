@@ -1,18 +1,29 @@
 package pkg
 
-import (
-	"sync"
-)
-
-// op bundles a move notation and its inverse for fast backtracking
-type op struct {
-	notation           string
-	face, count, width int
-	isPrime, isSlice   bool
+// FindAlgsParallelDFS launches one goroutine per first move and performs
+// in-place DFS with backtracking to find all sequences up to maxDepth. It is
+// FindPuzzleAlgs on a Cube.
+func FindAlgsParallelDFS(
+	initial *Cube,
+	moves []Move,
+	check CheckFunc,
+	maxDepth int,
+	progress chan<- struct{},
+) []Alg {
+	found := FindPuzzleAlgs(initial, MoveIndices(moves), check, maxDepth, progress)
+	solutions := make([]Alg, len(found))
+	for i, sol := range found {
+		solutions[i] = make(Alg, len(sol))
+		for j, m := range sol {
+			solutions[i][j] = MoveFromIndex(m)
+		}
+	}
+	return solutions
 }
 
-// FindSolutionsParallelDFS launches one goroutine per first move and performs
-// in-place DFS with backtracking to find all sequences up to maxDepth.
+// FindSolutionsParallelDFS is the string based form of FindAlgsParallelDFS. It
+// searches nothing if the move set does not parse; callers that need the
+// *ParseError check the set with ParseMoveSet first.
 func FindSolutionsParallelDFS(
 	initial *Cube,
 	moves []string,
@@ -20,95 +31,10 @@ func FindSolutionsParallelDFS(
 	maxDepth int,
 	progress chan<- struct{},
 ) [][]string {
-	// precompute ops
-	ops := make([]op, len(moves))
-	for i, m := range moves {
-		face, count, width, isPrime, isSlice := initial.parseNotation(m)
-		ops[i] = op{m, face, count, width, isPrime, isSlice}
+	set, err := ParseMoveSet(moves, initial.Size)
+	if err != nil {
+		Printf("invalid move set: %v\n", err)
+		return nil
 	}
-
-	var (
-		wg        sync.WaitGroup
-		solMu     sync.Mutex
-		solutions [][]string
-	)
-
-	// spawn one goroutine per first move
-	for _, root := range ops {
-		wg.Add(1)
-		go func(root op) {
-			defer wg.Done()
-
-			// === per-goroutine local buffer ===
-			var local [][]op
-
-			// one copy per branch
-			c := initial.Copy()
-			c.PerformFaceTurn(root.face, root.count, root.width, root.isPrime, root.isSlice)
-
-			// recursive DFS closure
-			var dfs func(c *Cube, path []op)
-			dfs = func(c *Cube, path []op) {
-				// tick progress
-				if progress != nil {
-					progress <- struct{}{}
-				}
-
-				l := len(path)
-
-				// record solution
-				if check(c) {
-					// deep-copy the path before recording
-					cp := make([]op, l)
-					copy(cp, path)
-					local = append(local, cp)
-					return
-				}
-				if l == maxDepth {
-					return
-				}
-
-				lastFace := path[l-1].face
-				for _, op := range ops {
-					if op.face == lastFace {
-						continue
-					}
-
-					// apply move
-					path = append(path, op)
-					c.PerformFaceTurn(op.face, op.count, op.width, op.isPrime, op.isSlice)
-
-					dfs(c, path)
-
-					// backtrack
-					path = path[:l]
-					c.PerformFaceTurn(op.face, op.count, op.width, !op.isPrime, op.isSlice)
-				}
-			}
-
-			// start path
-			path := make([]op, 0, maxDepth+1)
-			path = append(path, root)
-			dfs(c, path)
-
-			// merge once
-			solMu.Lock()
-
-			for _, ops := range local {
-				// make a []string the same length as this []op
-				seq := make([]string, len(ops))
-				// fill it with the notation of each op
-				for i, op := range ops {
-					seq[i] = op.notation
-				}
-				// now append that []string to your solutions
-				solutions = append(solutions, seq)
-			}
-
-			solMu.Unlock()
-		}(root)
-	}
-
-	wg.Wait()
-	return solutions
+	return algStrings(FindAlgsParallelDFS(initial, set, check, maxDepth, progress))
 }
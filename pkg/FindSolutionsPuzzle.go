@@ -0,0 +1,98 @@
+package pkg
+
+import (
+	"sync"
+)
+
+// FindPuzzleAlgs is FindAlgsParallelDFS for any Puzzle: it launches one
+// goroutine per first move and performs in-place DFS with backtracking,
+// never turning the same MoveGroup twice in a row. Puzzles implementing
+// Legality have illegal moves skipped. Solutions are lists of the move
+// values passed in.
+func FindPuzzleAlgs[P Puzzle[P]](
+	initial P,
+	moves []int,
+	check func(P) bool,
+	maxDepth int,
+	progress chan<- struct{},
+) [][]int {
+	var (
+		wg        sync.WaitGroup
+		solMu     sync.Mutex
+		solutions [][]int
+	)
+
+	// look up inverses and groups once, outside the hot loop
+	inverses := make([]int, len(moves))
+	groups := make([]int, len(moves))
+	for k, m := range moves {
+		inverses[k] = initial.InverseMove(m)
+		groups[k] = initial.MoveGroup(m)
+	}
+
+	// spawn one goroutine per first move
+	for r := range moves {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+
+			// === per-goroutine local buffer ===
+			var local [][]int
+
+			// one copy per branch
+			p := initial.Copy()
+			legal, constrained := any(p).(Legality)
+			if constrained && !legal.CanApply(moves[r]) {
+				return
+			}
+			p.Apply(moves[r])
+
+			// recursive DFS closure over positions in moves
+			var dfs func(path []int)
+			dfs = func(path []int) {
+				// tick progress
+				if progress != nil {
+					progress <- struct{}{}
+				}
+
+				l := len(path)
+
+				// record solution
+				if check(p) {
+					sol := make([]int, l)
+					for i, k := range path {
+						sol[i] = moves[k]
+					}
+					local = append(local, sol)
+					return
+				}
+				if l == maxDepth {
+					return
+				}
+
+				last := groups[path[l-1]]
+				for k, m := range moves {
+					if groups[k] == last || constrained && !legal.CanApply(m) {
+						continue
+					}
+					p.Apply(m)
+					dfs(append(path, k))
+					// backtrack
+					p.Apply(inverses[k])
+				}
+			}
+
+			path := make([]int, 1, maxDepth+1)
+			path[0] = r
+			dfs(path)
+
+			// merge once
+			solMu.Lock()
+			solutions = append(solutions, local...)
+			solMu.Unlock()
+		}(r)
+	}
+
+	wg.Wait()
+	return solutions
+}
@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"strings"
+)
+
+// Alg is a sequence of moves.
+type Alg []Move
+
+// ParseAlg parses s with ParseNotation and expands it to plain moves.
+func ParseAlg(s string) (Alg, error) {
+	seq, err := ParseNotation(s)
+	if err != nil {
+		return nil, err
+	}
+	alg := seq.Expand()
+	if alg == nil {
+		alg = Alg{}
+	}
+	return alg, nil
+}
+
+// ParseAlgStrings parses one move per element, as used by the string based
+// search functions.
+func ParseAlgStrings(moves []string) (Alg, error) {
+	alg := make(Alg, 0, len(moves))
+	for i, s := range moves {
+		m, err := ParseMove(s)
+		if err != nil {
+			return nil, &ParseError{Index: i, Token: s, Err: err}
+		}
+		alg = append(alg, m)
+	}
+	return alg, nil
+}
+
+// Validate reports the first move of a that cannot be performed on an n×n
+// cube as a *ParseError.
+func (a Alg) Validate(n int) error {
+	for i, m := range a {
+		if err := m.Validate(n); err != nil {
+			return moveError(i, m, err)
+		}
+	}
+	return nil
+}
+
+// String formats the alg as space separated moves.
+func (a Alg) String() string {
+	return strings.Join(a.Strings(), " ")
+}
+
+// Strings returns the notation of each move.
+func (a Alg) Strings() []string {
+	out := make([]string, len(a))
+	for i, m := range a {
+		out[i] = m.String()
+	}
+	return out
+}
+
+// Len returns the length of the alg under the given metric.
+func (a Alg) Len(metric Metric) int {
+	n := 0
+	for _, m := range a {
+		n += m.Len(metric)
+	}
+	return n
+}
+
+// Apply performs every move of the alg on c.
+func (a Alg) Apply(c *Cube) {
+	for _, m := range a {
+		m.Apply(c)
+	}
+}
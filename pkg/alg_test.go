@@ -0,0 +1,281 @@
+package pkg
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestParseAlgRoundTrip(t *testing.T) {
+	in := "R U2' Rw' 3Fw2 x y' z2 M E' S2 F"
+	alg, err := ParseAlg(in)
+	if err != nil {
+		t.Fatalf("ParseAlg(%q): %v", in, err)
+	}
+	if got := alg.String(); got != in {
+		t.Errorf("String() = %q, want %q", got, in)
+	}
+
+	wide, _ := ParseAlg("r u' f2")
+	if got := wide.String(); got != "Rw Uw' Fw2" {
+		t.Errorf("String() = %q, want %q", got, "Rw Uw' Fw2")
+	}
+
+	for _, bad := range []string{"X", "Q2", "Mw", "2x", "R U K"} {
+		if _, err := ParseAlg(bad); err == nil {
+			t.Errorf("ParseAlg(%q): expected error", bad)
+		}
+	}
+}
+
+func TestAlgLen(t *testing.T) {
+	alg, _ := ParseAlg("R U2 M' x y2 Rw'")
+	tests := []struct {
+		metric Metric
+		want   int
+	}{
+		{HTM, 5},
+		{QTM, 6},
+		{STM, 4},
+		{ETM, 6},
+	}
+	for _, tt := range tests {
+		if got := alg.Len(tt.metric); got != tt.want {
+			t.Errorf("Len(%d) = %d, want %d", tt.metric, got, tt.want)
+		}
+	}
+}
+
+func TestAlgApply(t *testing.T) {
+	alg, _ := ParseAlg("R U R' U' Rw2 E x' S")
+
+	a := NewCube(4)
+	alg.Apply(a)
+
+	b := NewCube(4)
+	for _, m := range alg {
+		if err := b.Move(m.String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for f := range 6 {
+		if string(a.Faces[f]) != string(b.Faces[f]) {
+			t.Fatalf("face %d differs between Alg.Apply and Cube.Move", f)
+		}
+	}
+
+	for i := len(alg) - 1; i >= 0; i-- {
+		alg[i].Inverse().Apply(a)
+	}
+	if !a.IsSolved() {
+		t.Error("expected cube to be solved after applying inverse, but it was not")
+	}
+}
+
+// mirrorCube reflects the sticker state of c across the plane
+// perpendicular to axis.
+func mirrorCube(c *Cube, axis Axis) *Cube {
+	n := c.Size
+	out := NewCube(n)
+	swap := func(f int) int {
+		if faceAxis[f] == axis {
+			return oppositeFace[f]
+		}
+		return f
+	}
+	for f := range 6 {
+		for i, v := range c.Faces[f] {
+			x, y, z := stickerPos(n, f, i)
+			switch axis {
+			case AxisX:
+				x = n - 1 - x
+			case AxisY:
+				y = n - 1 - y
+			case AxisZ:
+				z = n - 1 - z
+			}
+			out.Faces[swap(f)][stickerIndex(n, swap(f), x, y, z)] = byte(swap(int(v)))
+		}
+	}
+	return out
+}
+
+func sameState(a, b *Cube) bool {
+	for f := range 6 {
+		if string(a.Faces[f]) != string(b.Faces[f]) {
+			return false
+		}
+	}
+	return true
+}
+
+// randomAlg builds a random alg mixing face, wide, slice and rotation moves.
+func randomAlg(rng *rand.Rand, length int) Alg {
+	pool := strings.Fields("U D R L F B Uw Rw Fw Dw Lw Bw M E S x y z")
+	suffixes := []string{"", "'", "2", "2'"}
+	alg := make(Alg, length)
+	for i := range alg {
+		m, err := ParseMove(pool[rng.Intn(len(pool))] + suffixes[rng.Intn(len(suffixes))])
+		if err != nil {
+			panic(err)
+		}
+		alg[i] = m
+	}
+	return alg
+}
+
+func TestAlgInvert(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 2; n <= 5; n++ {
+		for range 50 {
+			alg := randomAlg(rng, 20)
+			c := NewCube(n)
+			alg.Apply(c)
+			alg.Invert().Apply(c)
+			if !c.IsSolved() {
+				t.Fatalf("%dx%d: %s followed by its inverse is not solved", n, n, alg)
+			}
+		}
+	}
+}
+
+func TestAlgMirror(t *testing.T) {
+	mirrored, _ := ParseAlg("R U R' U'")
+	if got := mirrored.Mirror(AxisX).String(); got != "L' U' L U" {
+		t.Errorf("Mirror(AxisX) = %q, want %q", got, "L' U' L U")
+	}
+
+	rng := rand.New(rand.NewSource(2))
+	for n := 2; n <= 5; n++ {
+		for _, axis := range []Axis{AxisX, AxisY, AxisZ} {
+			for range 30 {
+				alg := randomAlg(rng, 15)
+				want := NewCube(n)
+				alg.Apply(want)
+				want = mirrorCube(want, axis)
+
+				got := NewCube(n)
+				alg.Mirror(axis).Apply(got)
+				if !sameState(got, want) {
+					t.Fatalf("%dx%d: mirror %d of %s = %s does not match mirrored state", n, n, axis, alg, alg.Mirror(axis))
+				}
+			}
+		}
+	}
+}
+
+func TestAlgRotate(t *testing.T) {
+	alg, _ := ParseAlg("R U")
+	y, _ := ParseMove("y")
+	if got := alg.Rotate(y).String(); got != "B U" {
+		t.Errorf("Rotate(y) = %q, want %q", got, "B U")
+	}
+
+	rng := rand.New(rand.NewSource(3))
+	for n := 2; n <= 5; n++ {
+		for _, r := range strings.Fields("x x' x2 y y' y2 z z' z2") {
+			rot, _ := ParseMove(r)
+			for range 20 {
+				alg := randomAlg(rng, 15)
+				want := NewCube(n)
+				rot.Apply(want)
+				alg.Apply(want)
+				rot.Inverse().Apply(want)
+
+				got := NewCube(n)
+				alg.Rotate(rot).Apply(got)
+				if !sameState(got, want) {
+					t.Fatalf("%dx%d: %s rotated by %s = %s does not match", n, n, alg, rot, alg.Rotate(rot))
+				}
+			}
+		}
+	}
+}
+
+func TestAlgSimplify(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"R R2", "R'"},
+		{"R R'", ""},
+		{"R L R'", "L"},
+		{"R U U' R'", ""},
+		{"x M x'", "M"},
+		{"Rw R Rw'", "R"},
+		{"F2'", "F2"},
+	}
+	for _, tt := range tests {
+		alg, _ := ParseAlg(tt.in)
+		if got := alg.Simplify().String(); got != tt.want {
+			t.Errorf("Simplify(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(4))
+	for n := 2; n <= 5; n++ {
+		for range 50 {
+			alg := randomAlg(rng, 25)
+			want := NewCube(n)
+			alg.Apply(want)
+
+			got := NewCube(n)
+			alg.Simplify().Apply(got)
+			if !sameState(got, want) {
+				t.Fatalf("%dx%d: simplified %s = %s does not match", n, n, alg, alg.Simplify())
+			}
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in    string
+		index int
+		token string
+		err   error
+	}{
+		{"X Q", 0, "X", ErrUnknownMove},
+		{"R U Q", 2, "Q", ErrUnknownMove},
+		{"R R3", 1, "R3", ErrBadSuffix},
+		{"(R U) R''", 4, "R''", ErrBadSuffix},
+		{"R'2", 0, "R'2", ErrBadSuffix},
+		{"U 0R", 1, "0R", ErrBadWidth},
+		{"1Rw", 0, "1Rw", ErrBadWidth},
+		{"Rw rw", 1, "rw", ErrUnknownMove},
+		{"(R U)0", 4, "0", ErrSyntax},
+	}
+	for _, tt := range tests {
+		_, err := ParseAlg(tt.in)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("ParseAlg(%q) = %v, want *ParseError", tt.in, err)
+			continue
+		}
+		if pe.Index != tt.index || pe.Token != tt.token || !errors.Is(err, tt.err) {
+			t.Errorf("ParseAlg(%q) = %v, want token %d %q: %v", tt.in, err, tt.index+1, tt.token, tt.err)
+		}
+	}
+
+	// Validate counts moves of the expanded alg, not tokens
+	alg, err := ParseAlg("[R, U] 4R")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pe *ParseError
+	if err := alg.Validate(3); !errors.As(err, &pe) || pe.Index != -1 || pe.Move != 4 || !errors.Is(err, ErrBadWidth) {
+		t.Errorf("Validate(%q) = %v, want move 5 \"4R\": %v", alg, err, ErrBadWidth)
+	}
+
+	c := NewCube(3)
+	for _, bad := range []string{"X Q", "R 4Rw", "R U R' U' 4R2"} {
+		if err := c.Moves(bad); err == nil {
+			t.Errorf("Moves(%q): expected error", bad)
+		}
+	}
+	if !c.IsSolved() {
+		t.Error("expected rejected moves to leave the cube untouched")
+	}
+	if err := NewCube(2).Moves("R M"); !errors.Is(err, ErrBadWidth) {
+		t.Errorf("Moves(\"R M\") on 2x2 = %v, want ErrBadWidth", err)
+	}
+}
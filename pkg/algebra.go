@@ -0,0 +1,142 @@
+package pkg
+
+// Axis identifies one of the three cube axes.
+type Axis int
+
+const (
+	AxisX Axis = iota // through R and L
+	AxisY             // through U and D
+	AxisZ             // through F and B
+)
+
+// oppositeFace maps each face to the face across the cube from it.
+var oppositeFace = [6]int{Uface: Dface, Rface: Lface, Fface: Bface, Dface: Uface, Lface: Rface, Bface: Fface}
+
+// faceAxis maps each face to the axis it turns around.
+var faceAxis = [6]Axis{Uface: AxisY, Dface: AxisY, Rface: AxisX, Lface: AxisX, Fface: AxisZ, Bface: AxisZ}
+
+// rotationCycle lists, for each rotation face, where a clockwise quarter
+// rotation carries the side faces: content on cycle[i] moves to cycle[i+1].
+var rotationCycle = [6][4]int{
+	Uface: {Fface, Lface, Bface, Rface}, // y
+	Rface: {Fface, Uface, Bface, Dface}, // x
+	Fface: {Uface, Rface, Dface, Lface}, // z
+}
+
+// Invert returns the alg that undoes a.
+func (a Alg) Invert() Alg {
+	out := make(Alg, len(a))
+	for i, m := range a {
+		out[len(a)-1-i] = m.Inverse()
+	}
+	return out
+}
+
+// Mirror returns a reflected across the plane perpendicular to axis, so
+// AxisX swaps L and R (R U R' becomes L' U' L).
+func (a Alg) Mirror(axis Axis) Alg {
+	out := make(Alg, len(a))
+	for i, m := range a {
+		if faceAxis[m.Face] == axis {
+			m.Face = oppositeFace[m.Face]
+		}
+		m.Amount = -m.Amount
+		out[i] = m.normalizeFace()
+	}
+	return out
+}
+
+// Rotate rewrites a as performed after the whole-cube rotation rot, so that
+// rot a rot' and a.Rotate(rot) have the same effect; for example
+// "R U".Rotate(y) is "B U". Only rot's axis and amount are used.
+func (a Alg) Rotate(rot Move) Alg {
+	turns := ((rot.Amount % 4) + 4) % 4
+	cycle := rotationCycle[rot.Face]
+	if rot.Face != Uface && rot.Face != Rface && rot.Face != Fface {
+		cycle = rotationCycle[oppositeFace[rot.Face]]
+		turns = (4 - turns) % 4
+	}
+
+	// preimage[f] is the face whose content lands on f after the rotation
+	var preimage [6]int
+	for f := range preimage {
+		preimage[f] = f
+	}
+	for range turns {
+		next := preimage
+		for i := range cycle {
+			next[cycle[(i+1)%4]] = preimage[cycle[i]]
+		}
+		preimage = next
+	}
+
+	out := make(Alg, len(a))
+	for i, m := range a {
+		m.Face = preimage[m.Face]
+		out[i] = m.normalizeFace()
+	}
+	return out
+}
+
+// normalizeFace makes slices and rotations follow their notation face
+// (M→L, E→D, S→F, x→R, y→U, z→F), reversing direction when the move was
+// expressed about the opposite face.
+func (m Move) normalizeFace() Move {
+	var letters [6]byte
+	switch m.Kind {
+	case MoveSlice, MoveWideSlice:
+		letters = sliceLetters
+	case MoveRotation:
+		letters = rotationLetters
+	default:
+		return m
+	}
+	if letters[m.Face] == 0 {
+		m.Face = oppositeFace[m.Face]
+		m.Amount = -m.Amount
+	}
+	return m
+}
+
+// Simplify cancels and merges moves, e.g. "R R2" becomes "R'" and
+// "R L R'" becomes "L". Moves on the same axis commute, so a move merges
+// with any identical turn it can reach across same-axis moves.
+func (a Alg) Simplify() Alg {
+	out := make(Alg, 0, len(a))
+	for _, m := range a {
+		merged := false
+		for j := len(out) - 1; j >= 0 && faceAxis[out[j].Face] == faceAxis[m.Face]; j-- {
+			o := out[j]
+			if o.Face != m.Face || o.Kind != m.Kind || o.Layers != m.Layers || o.Offset != m.Offset {
+				continue
+			}
+			o.Amount = normalizeAmount(o.Amount + m.Amount)
+			if o.Amount == 0 {
+				out = append(out[:j], out[j+1:]...)
+			} else {
+				out[j] = o
+			}
+			merged = true
+			break
+		}
+		if !merged {
+			if m.Amount = normalizeAmount(m.Amount); m.Amount != 0 {
+				out = append(out, m)
+			}
+		}
+	}
+	return out
+}
+
+// normalizeAmount reduces a quarter-turn count to 0, 1, 2 or -1.
+func normalizeAmount(amount int) int {
+	switch ((amount % 4) + 4) % 4 {
+	case 1:
+		return 1
+	case 2:
+		return 2
+	case 3:
+		return -1
+	}
+	return 0
+}
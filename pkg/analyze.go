@@ -0,0 +1,277 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Perm is a permutation of the 6n² stickers of an n×n cube, numbered in
+// facelet order (see Facelets) from 0: the sticker at k moves to p[k].
+type Perm []int
+
+// StickerPerm returns the permutation alg performs on the stickers of an
+// n×n cube. Stickers are tracked by labelling up to 255 of them per pass.
+func StickerPerm(alg Alg, n int) Perm {
+	total := 6 * n * n
+	p := make(Perm, total)
+	for base := 0; base < total; base += 255 {
+		c := NewCube(n)
+		for f := range 6 {
+			for i := range c.Faces[f] {
+				label := f*n*n + i - base
+				if label < 0 || label >= 255 {
+					label = 255
+				}
+				c.Faces[f][i] = byte(label)
+			}
+		}
+		alg.Apply(c)
+		for f := range 6 {
+			for i, v := range c.Faces[f] {
+				if v != 255 {
+					p[base+int(v)] = f*n*n + i
+				}
+			}
+		}
+	}
+	return p
+}
+
+// Cycles returns the cycles of p longer than one, each starting from its
+// smallest point.
+func (p Perm) Cycles() [][]int {
+	var cycles [][]int
+	seen := make([]bool, len(p))
+	for k := range p {
+		if seen[k] || p[k] == k {
+			continue
+		}
+		var cycle []int
+		for j := k; !seen[j]; j = p[j] {
+			seen[j] = true
+			cycle = append(cycle, j)
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// Order returns how many times p must be repeated to give the identity.
+func (p Perm) Order() int {
+	order := 1
+	for _, cycle := range p.Cycles() {
+		order = lcm(order, len(cycle))
+	}
+	return order
+}
+
+// Parity returns 0 for an even permutation and 1 for an odd one.
+func (p Perm) Parity() int {
+	return permParity(p)
+}
+
+// GAP formats p in GAP's cycle notation, numbering stickers from 1.
+func (p Perm) GAP() string {
+	cycles := p.Cycles()
+	if len(cycles) == 0 {
+		return "()"
+	}
+	var b strings.Builder
+	for _, cycle := range cycles {
+		b.WriteByte('(')
+		for i, k := range cycle {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Itoa(k + 1))
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func lcm(a, b int) int { return a / gcd(a, b) * b }
+
+// GAPGroup exports algs as generators of a permutation group on the stickers
+// of an n×n cube, as a GAP script defining g1, g2, ... and G.
+func GAPGroup(n int, gens []Alg) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %dx%d cube, stickers numbered 1..%d in facelet order (URFDLB)\n", n, n, 6*n*n)
+	names := make([]string, len(gens))
+	for i, gen := range gens {
+		names[i] = "g" + strconv.Itoa(i+1)
+		fmt.Fprintf(&b, "%s := %s; # %s\n", names[i], StickerPerm(gen, n).GAP(), gen)
+	}
+	fmt.Fprintf(&b, "G := Group(%s);\n", strings.Join(names, ", "))
+	return b.String()
+}
+
+// Cycle is a cycle of corners or edges, listing the positions a piece
+// visits, with the twist (corners, 0-2 clockwise) or flip (edges, 0-1) it
+// picks up on the way round.
+type Cycle struct {
+	Pieces []string
+	Twist  int
+}
+
+// String formats the cycle as e.g. (URF UBR ULB), marking a net clockwise
+// twist with +, an anticlockwise one with - and a flip with '.
+func (cy Cycle) String() string {
+	s := "(" + strings.Join(cy.Pieces, " ") + ")"
+	switch {
+	case cy.Twist == 0:
+	case len(cy.Pieces[0]) == 2:
+		s += "'"
+	case cy.Twist == 1:
+		s += "+"
+	default:
+		s += "-"
+	}
+	return s
+}
+
+// Analysis describes what an alg does to an n×n cube.
+type Analysis struct {
+	Size     int
+	Stickers Perm
+
+	// Corners and the middle Edges of odd cubes, as cycles of positions
+	// that move or turn in place.
+	Corners []Cycle
+	Edges   []Cycle
+
+	// CornerParity and EdgeParity are 0 for even piece permutations.
+	CornerParity int
+	EdgeParity   int
+
+	// Order is how many times the alg must be repeated before the cube
+	// looks solved again; identical stickers, such as the centers of big
+	// cubes, can make it a divisor of Stickers.Order().
+	Order int
+}
+
+// Analyze works out the piece cycles, parities and order of alg on an n×n
+// cube.
+func Analyze(alg Alg, n int) (*Analysis, error) {
+	if err := alg.Validate(n); err != nil {
+		return nil, err
+	}
+	a := &Analysis{Size: n, Stickers: StickerPerm(alg, n)}
+	c := NewCube(n)
+	alg.Apply(c)
+
+	var cp [8]int
+	var co [8]int
+	for pos := range cp {
+		piece, twist, ok := c.CornerAt(Corner(pos))
+		if !ok {
+			return nil, fmt.Errorf("no corner at %s", Corner(pos))
+		}
+		cp[pos], co[pos] = int(piece), twist
+	}
+	a.Corners = pieceCycles(cp[:], co[:], 3, func(i int) string { return Corner(i).String() })
+	a.CornerParity = permParity(cp[:])
+
+	if n%2 == 1 {
+		var ep [12]int
+		var eo [12]int
+		for pos := range ep {
+			piece, flip, ok := c.EdgeAt(Edge(pos))
+			if !ok {
+				return nil, fmt.Errorf("no edge at %s", Edge(pos))
+			}
+			ep[pos], eo[pos] = int(piece), flip
+		}
+		a.Edges = pieceCycles(ep[:], eo[:], 2, func(i int) string { return Edge(i).String() })
+		a.EdgeParity = permParity(ep[:])
+	}
+
+	// the cube looks solved after r repeats when the colors along every
+	// sticker cycle repeat with a period dividing r
+	a.Order = 1
+	for _, cycle := range a.Stickers.Cycles() {
+		a.Order = lcm(a.Order, colorPeriod(cycle, n))
+	}
+	return a, nil
+}
+
+// colorPeriod returns the smallest shift d dividing len(cycle) that maps
+// the solved colors along the cycle onto themselves.
+func colorPeriod(cycle []int, n int) int {
+	l := len(cycle)
+	for d := 1; d < l; d++ {
+		if l%d != 0 {
+			continue
+		}
+		ok := true
+		for j := range cycle {
+			if cycle[j]/(n*n) != cycle[(j+d)%l]/(n*n) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return d
+		}
+	}
+	return l
+}
+
+// pieceCycles lists the cycles of a piece permutation, where perm[pos] is
+// the piece at pos and ori[pos] its orientation modulo mod. Pieces that stay
+// put are listed only when they are twisted or flipped.
+func pieceCycles(perm, ori []int, mod int, name func(int) string) []Cycle {
+	dest := make([]int, len(perm))
+	for pos, piece := range perm {
+		dest[piece] = pos
+	}
+	var cycles []Cycle
+	seen := make([]bool, len(perm))
+	for start := range perm {
+		if seen[start] {
+			continue
+		}
+		var cy Cycle
+		for pos := start; !seen[pos]; pos = dest[pos] {
+			seen[pos] = true
+			cy.Pieces = append(cy.Pieces, name(pos))
+			cy.Twist += ori[dest[pos]]
+		}
+		cy.Twist %= mod
+		if len(cy.Pieces) > 1 || cy.Twist != 0 {
+			cycles = append(cycles, cy)
+		}
+	}
+	return cycles
+}
+
+// String prints the analysis, one property per line.
+func (a *Analysis) String() string {
+	var b strings.Builder
+	list := func(cycles []Cycle) string {
+		if len(cycles) == 0 {
+			return "none"
+		}
+		parts := make([]string, len(cycles))
+		for i, cy := range cycles {
+			parts[i] = cy.String()
+		}
+		return strings.Join(parts, " ")
+	}
+	parity := [2]string{"even", "odd"}
+	fmt.Fprintf(&b, "corners: %s (%s)\n", list(a.Corners), parity[a.CornerParity])
+	if a.Size%2 == 1 {
+		fmt.Fprintf(&b, "edges: %s (%s)\n", list(a.Edges), parity[a.EdgeParity])
+	}
+	fmt.Fprintf(&b, "order: %d\n", a.Order)
+	fmt.Fprintf(&b, "stickers: %s", a.Stickers.GAP())
+	return b.String()
+}
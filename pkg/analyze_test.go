@@ -0,0 +1,125 @@
+package pkg
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		n                  int
+		alg                string
+		corners, edges     string
+		cornerPar, edgePar int
+		order              int
+	}{
+		{3, "U", "(URF UFL ULB UBR)", "(UR UF UL UB)", 1, 1, 4},
+		{3, "R U R' U' R' F R2 U' R' U' R U R' F'", "(URF UBR)", "(UR UL)", 1, 1, 2},
+		{3, "R U R' U R U2 R' U2", "(URF)+ (ULB)+ (UBR)+", "(UR UF UB)", 0, 0, 3},
+		{3, "M' U M U2 M' U M", "none", "(UR UL UB)", 0, 0, 3},
+		{2, "R", "(URF UBR DRB DFR)", "", 1, 0, 4},
+		{3, "R U", "(URF)+ (UFL ULB UBR DRB DFR)-", "", 0, 0, 105},
+	}
+	for _, tt := range tests {
+		alg, err := ParseAlg(tt.alg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, err := Analyze(alg, tt.n)
+		if err != nil {
+			t.Fatalf("Analyze(%q): %v", tt.alg, err)
+		}
+		if got := cyclesString(a.Corners); got != tt.corners {
+			t.Errorf("%q: corners %s, want %s", tt.alg, got, tt.corners)
+		}
+		if tt.edges != "" {
+			if got := cyclesString(a.Edges); got != tt.edges {
+				t.Errorf("%q: edges %s, want %s", tt.alg, got, tt.edges)
+			}
+		}
+		if a.CornerParity != tt.cornerPar || a.EdgeParity != tt.edgePar {
+			t.Errorf("%q: parities %d, %d; want %d, %d", tt.alg, a.CornerParity, a.EdgeParity, tt.cornerPar, tt.edgePar)
+		}
+		if a.Order != tt.order {
+			t.Errorf("%q: order %d, want %d", tt.alg, a.Order, tt.order)
+		}
+	}
+}
+
+func cyclesString(cycles []Cycle) string {
+	if len(cycles) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(cycles))
+	for i, cy := range cycles {
+		parts[i] = cy.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestCycleString(t *testing.T) {
+	for _, tt := range []struct {
+		cy   Cycle
+		want string
+	}{
+		{Cycle{Pieces: []string{"UF"}, Twist: 1}, "(UF)'"},
+		{Cycle{Pieces: []string{"URF", "UBR"}, Twist: 2}, "(URF UBR)-"},
+		{Cycle{Pieces: []string{"UR", "UL"}}, "(UR UL)"},
+	} {
+		if got := tt.cy.String(); got != tt.want {
+			t.Errorf("%+v: got %s, want %s", tt.cy, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for n := 3; n <= 5; n++ {
+		for range 20 {
+			alg := randomAlg(rng, 4)
+			a, err := Analyze(alg, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Stickers.Order()%a.Order != 0 {
+				t.Errorf("%dx%d %q: order %d does not divide the sticker order %d", n, n, alg, a.Order, a.Stickers.Order())
+			}
+			c := NewCube(n)
+			for k := 1; k <= a.Order; k++ {
+				alg.Apply(c)
+				if c.IsSolved() != (k == a.Order) {
+					t.Errorf("%dx%d %q: solved after %d repeats, order %d", n, n, alg, k, a.Order)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestStickerPerm(t *testing.T) {
+	// 7x7 has more stickers than fit in one byte-labelled pass
+	alg, _ := ParseAlg("3Rw U' 2-5Fw2 m x S")
+	p, q := StickerPerm(alg, 7), StickerPerm(alg.Invert(), 7)
+	for k := range p {
+		if q[p[k]] != k {
+			t.Fatalf("sticker %d: inverse alg does not bring it back", k)
+		}
+	}
+	if p.Parity() != q.Parity() {
+		t.Error("an alg and its inverse should have the same parity")
+	}
+
+	u, _ := ParseAlg("U")
+	if got := StickerPerm(u, 2).GAP(); !strings.HasPrefix(got, "(1,2,4,3)") {
+		t.Errorf("2x2 U = %s, want the U face cycle (1,2,4,3) first", got)
+	}
+	if got := StickerPerm(Alg{}, 2).GAP(); got != "()" {
+		t.Errorf("identity = %s, want ()", got)
+	}
+	r, _ := ParseAlg("R")
+	gap := GAPGroup(3, []Alg{u, r})
+	if !strings.Contains(gap, "g2 := (") || !strings.HasSuffix(gap, "G := Group(g1, g2);\n") {
+		t.Errorf("GAPGroup:\n%s", gap)
+	}
+}
@@ -0,0 +1,55 @@
+package pkg
+
+// AUFs are the U-layer adjustments tried before and after an alg: none, U,
+// U2 and U'.
+var AUFs = [4]Alg{
+	{},
+	{{Face: Uface, Layers: 1, Amount: 1, Kind: MoveFace}},
+	{{Face: Uface, Layers: 1, Amount: 2, Kind: MoveFace}},
+	{{Face: Uface, Layers: 1, Amount: -1, Kind: MoveFace}},
+}
+
+// aufUndo is the U turn that undoes k clockwise quarter turns.
+var aufUndo = [4]int{0, -1, 2, 1}
+
+// IsAUF reports whether m turns only the outer U layer.
+func (m Move) IsAUF() bool {
+	return m.Kind == MoveFace && m.Face == Uface && m.Layers == 1 && m.Offset == 0
+}
+
+// aufIndex returns the index in AUFs of the first adjustment after which
+// check holds, or -1. It turns c in place and leaves it as it found it.
+func (c *Cube) aufIndex(check CheckFunc) int {
+	for k := range AUFs {
+		if k > 0 {
+			c.turnLayers(Uface, 1, 1, 1)
+		}
+		if check(c) {
+			if k > 0 {
+				c.turnLayers(Uface, 1, 1, aufUndo[k])
+			}
+			return k
+		}
+	}
+	c.turnLayers(Uface, 1, 1, 1)
+	return -1
+}
+
+// AUF returns the U-layer adjustment after which check holds on c, empty if
+// it already holds, and false if no adjustment works.
+func (c *Cube) AUF(check CheckFunc) (Alg, bool) {
+	k := c.aufIndex(check)
+	if k < 0 {
+		return nil, false
+	}
+	return append(Alg{}, AUFs[k]...), true
+}
+
+// AnyAUF returns a CheckFunc accepting a cube on which check holds after
+// some U-layer adjustment, so that searches do not spend moves on a final
+// AUF.
+func AnyAUF(check CheckFunc) CheckFunc {
+	return func(c *Cube) bool {
+		return c.aufIndex(check) >= 0
+	}
+}
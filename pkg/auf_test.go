@@ -0,0 +1,35 @@
+package pkg
+
+import "testing"
+
+func TestAUF(t *testing.T) {
+	goal := AnyAUF((*Cube).IsSolved)
+	for _, tt := range []struct {
+		alg, post string
+		ok        bool
+	}{
+		{"", "", true},
+		{"U", "U'", true},
+		{"U2", "U2", true},
+		{"U'", "U", true},
+		{"(R U R' U')6 U2", "U2", true},
+		{"R U R'", "", false},
+	} {
+		for n := 2; n <= 4; n++ {
+			c := NewCube(n)
+			c.Moves(tt.alg)
+			before := c.Copy()
+
+			if goal(c) != tt.ok {
+				t.Errorf("%dx%d %q: AnyAUF = %v, want %v", n, n, tt.alg, !tt.ok, tt.ok)
+			}
+			post, ok := c.AUF((*Cube).IsSolved)
+			if ok != tt.ok || ok && post.String() != tt.post {
+				t.Errorf("%dx%d %q: AUF = %q, %v; want %q, %v", n, n, tt.alg, post, ok, tt.post, tt.ok)
+			}
+			if !c.Equal(before) {
+				t.Errorf("%dx%d %q: AUF checks changed the cube", n, n, tt.alg)
+			}
+		}
+	}
+}
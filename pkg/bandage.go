@@ -0,0 +1,196 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bandage fuses pieces of a cube into blocks that only move together, so
+// whether a move is legal depends on where the blocks are. It labels every
+// sticker with the block its piece belongs to, 0 for free pieces, and the
+// labels are turned along with the cube.
+type Bandage struct {
+	labels *Cube
+	blocks int
+}
+
+// ParseBandage parses a bandage pattern for an n×n cube in its solved
+// state: blocks separated by spaces or semicolons, each a list of pieces
+// joined by +. A piece is named by the faces it touches, in any order: U
+// is a centre, UF an edge and UFR a corner. On even cubes only corners can
+// be named, since there are no middle edges or centres. For example
+// "UF+UFR+U" fuses a corner, edge and centre into a 1x2x2 block.
+func ParseBandage(s string, n int) (*Bandage, error) {
+	b := &Bandage{labels: NewCube(n)}
+	for f := range b.labels.Faces {
+		clear(b.labels.Faces[f])
+	}
+	blocks := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ';' })
+	for _, block := range blocks {
+		pieces := strings.Split(block, "+")
+		if len(pieces) < 2 {
+			return nil, fmt.Errorf("block %q fuses fewer than two pieces", block)
+		}
+		b.blocks++
+		for _, piece := range pieces {
+			x, y, z, err := piecePos(piece, n)
+			if err != nil {
+				return nil, fmt.Errorf("block %q: %w", block, err)
+			}
+			for f := range 6 {
+				if !onFace(n, f, x, y, z) {
+					continue
+				}
+				i := stickerIndex(n, f, x, y, z)
+				if b.labels.Faces[f][i] != 0 {
+					return nil, fmt.Errorf("piece %s is in two blocks", piece)
+				}
+				b.labels.Faces[f][i] = byte(b.blocks)
+			}
+		}
+	}
+	return b, nil
+}
+
+// piecePos returns the cubie coordinates of a piece named by its faces.
+func piecePos(name string, n int) (x, y, z int, err error) {
+	pos := [3]int{-1, -1, -1}
+	for _, r := range name {
+		f := strings.IndexRune(faceLetters, r)
+		if f < 0 {
+			return 0, 0, 0, fmt.Errorf("invalid piece %q", name)
+		}
+		for a, d := range faceNormal[f] {
+			switch {
+			case d == 0:
+			case pos[a] >= 0:
+				return 0, 0, 0, fmt.Errorf("invalid piece %q", name)
+			case d > 0:
+				pos[a] = n - 1
+			default:
+				pos[a] = 0
+			}
+		}
+	}
+	for a := range pos {
+		if pos[a] < 0 {
+			if n%2 == 0 {
+				return 0, 0, 0, fmt.Errorf("no piece %q on a %dx%d cube", name, n, n)
+			}
+			pos[a] = n / 2
+		}
+	}
+	return pos[0], pos[1], pos[2], nil
+}
+
+// Copy returns a copy of the bandage.
+func (b *Bandage) Copy() *Bandage {
+	return &Bandage{labels: b.labels.Copy(), blocks: b.blocks}
+}
+
+// Apply moves the blocks along with move m. It does not check that m is
+// allowed.
+func (b *Bandage) Apply(m Move) { m.Apply(b.labels) }
+
+// Allows reports whether m keeps every block in one piece: each block must
+// lie wholly inside or wholly outside the turning layers. Rotations are
+// always allowed.
+func (b *Bandage) Allows(m Move) bool {
+	if b.blocks == 0 || m.IsRotation() {
+		return true
+	}
+	n := b.labels.Size
+	lo, hi := m.layerRange(n)
+	// 0 unseen, 1 turning, 2 staying, per block
+	side := make([]byte, b.blocks+1)
+	for f := range 6 {
+		for i, k := range b.labels.Faces[f] {
+			if k == 0 {
+				continue
+			}
+			x, y, z := stickerPos(n, f, i)
+			layer := cubieLayer(n, m.Face, x, y, z)
+			s := byte(2)
+			if layer >= lo && layer <= hi {
+				s = 1
+			}
+			if side[k] == 0 {
+				side[k] = s
+			} else if side[k] != s {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// cubieLayer returns the layer, counted from 1 at face f, holding the
+// cubie at (x, y, z).
+func cubieLayer(n, f, x, y, z int) int {
+	pos := [3]int{x, y, z}
+	for a, d := range faceNormal[f] {
+		switch d {
+		case 1:
+			return n - pos[a]
+		case -1:
+			return pos[a] + 1
+		}
+	}
+	return 0
+}
+
+// Check applies alg to a copy of the bandage, failing with ErrBadTurn at
+// the first move a block does not allow.
+func (b *Bandage) Check(alg Alg) error {
+	b = b.Copy()
+	for i, m := range alg {
+		if !b.Allows(m) {
+			return moveError(i, m, fmt.Errorf("%w: blocked by the bandage", ErrBadTurn))
+		}
+		b.Apply(m)
+	}
+	return nil
+}
+
+// BandagedCube is a cube with a bandage, as a Puzzle for the generic
+// search. Moves are the indices of Move.Index; the search skips those the
+// bandage does not allow.
+type BandagedCube struct {
+	Cube    *Cube
+	Bandage *Bandage
+}
+
+var (
+	_ Puzzle[*BandagedCube] = (*BandagedCube)(nil)
+	_ Legality              = (*BandagedCube)(nil)
+)
+
+// Copy returns an independent copy of the cube and its bandage.
+func (b *BandagedCube) Copy() *BandagedCube {
+	return &BandagedCube{Cube: b.Cube.Copy(), Bandage: b.Bandage.Copy()}
+}
+
+// Apply performs a move on the cube and its bandage.
+func (b *BandagedCube) Apply(move int) {
+	m := MoveFromIndex(move)
+	m.Apply(b.Cube)
+	b.Bandage.Apply(m)
+}
+
+// CanApply reports whether the bandage allows the move.
+func (b *BandagedCube) CanApply(move int) bool { return b.Bandage.Allows(MoveFromIndex(move)) }
+
+// InverseMove returns the index of the inverse move.
+func (b *BandagedCube) InverseMove(move int) int { return b.Cube.InverseMove(move) }
+
+// Key returns the stickers and block labels as a string.
+func (b *BandagedCube) Key() string { return b.Cube.Key() + b.Bandage.labels.Key() }
+
+// IsSolved reports whether the cube is solved.
+func (b *BandagedCube) IsSolved() bool { return b.Cube.IsSolved() }
+
+// MoveNotation formats a move index in SiGN notation.
+func (b *BandagedCube) MoveNotation(move int) string { return b.Cube.MoveNotation(move) }
+
+// MoveGroup groups moves as Cube.MoveGroup does.
+func (b *BandagedCube) MoveGroup(move int) int { return b.Cube.MoveGroup(move) }
@@ -0,0 +1,87 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseBandage(t *testing.T) {
+	if _, err := ParseBandage("UF+UFR U+FU; DRB+DR+D+DB", 3); err == nil {
+		t.Error("UF in two blocks: expected error")
+	}
+	if _, err := ParseBandage("UF+UFR  U+FL; DRB+DR+D+DB", 3); err != nil {
+		t.Errorf("valid pattern: %v", err)
+	}
+	for _, bad := range []string{"UF", "UF+XY", "UF+UD", "UF+UFRL"} {
+		if _, err := ParseBandage(bad, 3); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+	if _, err := ParseBandage("UFR+UF", 2); err == nil {
+		t.Error("edge on a 2x2: expected error")
+	}
+	if _, err := ParseBandage("UFR+UFL", 4); err != nil {
+		t.Errorf("corners on a 4x4: %v", err)
+	}
+}
+
+func TestBandageAllows(t *testing.T) {
+	b, _ := ParseBandage("UF+UFR", 3)
+	for s, want := range map[string]bool{
+		"U": true, "F2": true, "D": true, "L'": true, "x": true, "y'": true,
+		"R": false, "M": false, "S'": true, "E": true, "Rw": true, "Lw": false,
+	} {
+		m, _ := ParseMove(s)
+		if got := b.Allows(m); got != want {
+			t.Errorf("Allows(%s) = %v, want %v", s, got, want)
+		}
+	}
+
+	// after F the block sits in the R layer, so R is allowed
+	alg, _ := ParseAlg("F R U R' F'")
+	if err := b.Check(alg); err != nil {
+		t.Errorf("F R U R' F': %v", err)
+	}
+	alg, _ = ParseAlg("F R U R' U' F'")
+	var pe *ParseError
+	if err := b.Check(alg); !errors.As(err, &pe) || pe.Index != -1 || pe.Move != 5 || !errors.Is(err, ErrBadTurn) {
+		t.Errorf("F R U R' U' F': got %v, want ErrBadTurn at 5", err)
+	}
+}
+
+func TestFindBandagedAlgs(t *testing.T) {
+	b, _ := ParseBandage("UF+UFR", 3)
+	scramble, _ := ParseAlg("F U F' U'")
+	c := NewCube(3)
+	scramble.Apply(c)
+	for _, m := range scramble {
+		b.Apply(m)
+	}
+	moves, _ := ParseMoveSet([]string{"U", "U'", "U2", "F", "F'", "F2", "R", "R'", "R2"}, 3)
+	// a loose goal has many solutions, some of which break the bandage
+	check := func(c *Cube) bool {
+		p, twist, ok := c.CornerAt(DFR)
+		return ok && p == DFR && twist == 0
+	}
+
+	// the bandaged search finds exactly the free solutions the bandage allows
+	free := FindAlgsParallelDFS(c, moves, check, 4, nil)
+	bandaged := FindBandagedAlgs(&BandagedCube{Cube: c, Bandage: b}, moves, check, 4, nil)
+	want := make(map[string]bool)
+	for _, sol := range free {
+		if b.Check(sol) == nil {
+			want[sol.String()] = true
+		}
+	}
+	if len(want) == 0 || len(want) == len(free) {
+		t.Fatalf("%d of %d free solutions are legal", len(want), len(free))
+	}
+	if len(bandaged) != len(want) {
+		t.Errorf("%d bandaged solutions, want %d", len(bandaged), len(want))
+	}
+	for _, sol := range bandaged {
+		if !want[sol.String()] {
+			t.Errorf("%s is not a legal free solution", sol)
+		}
+	}
+}
@@ -17,9 +17,18 @@ const (
 // Cube uses a fixed array of byte-slices for faces: 0=U,1=R,2=F,3=D,4=L,5=B
 // Each byte stores the face index of that sticker.
 type Cube struct {
-	Size   int
-	Faces  [6][]byte
-	buffer []byte // reusable temp buffer
+	Size  int
+	Faces [6][]byte
+	// Stickers tracks every sticker face by face like Faces, as on a
+	// supercube or picture cube: the position it started in, as
+	// face*n*n + index, shifted left 2 bits, plus the quarter turns it has
+	// made clockwise since. It is nil when stickers are not tracked; see
+	// TrackOrientation.
+	Stickers []int32
+	buffer   []byte // reusable temp buffer
+
+	stickerTurns *stickerTurns // turn tables while Stickers is tracked
+	stickerBuf   []int32       // reusable temp buffer for Stickers
 }
 
 // colorChar maps face indices to display letters
@@ -45,6 +54,11 @@ func (c *Cube) Copy() *Cube {
 		newC.Faces[f] = make([]byte, n*n)
 		copy(newC.Faces[f], c.Faces[f])
 	}
+	if c.Stickers != nil {
+		newC.Stickers = append([]int32(nil), c.Stickers...)
+		newC.stickerTurns = c.stickerTurns
+		newC.stickerBuf = make([]int32, len(c.Stickers))
+	}
 	return newC
 }
 
@@ -283,6 +297,9 @@ func (c *Cube) rotateFaceCCWBytes(face int) {
 
 // Face turns
 func (c *Cube) MoveU(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Uface, width, false)
+	}
 	n := c.Size
 	faces := c.Faces
 	f, r, b, l := faces[Fface], faces[Rface], faces[Bface], faces[Lface]
@@ -309,6 +326,9 @@ func (c *Cube) MoveU(width int) {
 }
 
 func (c *Cube) MoveUPrime(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Uface, width, true)
+	}
 	n := c.Size
 	faces := c.Faces
 	f, r, b, l := faces[Fface], faces[Rface], faces[Bface], faces[Lface]
@@ -335,6 +355,9 @@ func (c *Cube) MoveUPrime(width int) {
 }
 
 func (c *Cube) MoveD(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Dface, width, false)
+	}
 	n := c.Size
 	faces := c.Faces
 	f, r, b, l := faces[Fface], faces[Rface], faces[Bface], faces[Lface]
@@ -362,6 +385,9 @@ func (c *Cube) MoveD(width int) {
 }
 
 func (c *Cube) MoveDPrime(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Dface, width, true)
+	}
 	n := c.Size
 	faces := c.Faces
 	f, r, b, l := faces[Fface], faces[Rface], faces[Bface], faces[Lface]
@@ -389,6 +415,9 @@ func (c *Cube) MoveDPrime(width int) {
 }
 
 func (c *Cube) MoveR(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Rface, width, false)
+	}
 	n := c.Size
 	faces := c.Faces
 	u, f, d, b := faces[Uface], faces[Fface], faces[Dface], faces[Bface]
@@ -418,6 +447,9 @@ func (c *Cube) MoveR(width int) {
 }
 
 func (c *Cube) MoveRPrime(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Rface, width, true)
+	}
 	n := c.Size
 	faces := c.Faces
 	u, f, d, b := faces[Uface], faces[Fface], faces[Dface], faces[Bface]
@@ -447,6 +479,9 @@ func (c *Cube) MoveRPrime(width int) {
 }
 
 func (c *Cube) MoveL(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Lface, width, false)
+	}
 	n := c.Size
 	faces := c.Faces
 	u, f, d, b := faces[Uface], faces[Fface], faces[Dface], faces[Bface]
@@ -476,6 +511,9 @@ func (c *Cube) MoveL(width int) {
 }
 
 func (c *Cube) MoveLPrime(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Lface, width, true)
+	}
 	n := c.Size
 	faces := c.Faces
 	u, f, d, b := faces[Uface], faces[Fface], faces[Dface], faces[Bface]
@@ -505,6 +543,9 @@ func (c *Cube) MoveLPrime(width int) {
 }
 
 func (c *Cube) MoveF(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Fface, width, false)
+	}
 	n := c.Size
 	faces := c.Faces
 	u, r, d, l := faces[Uface], faces[Rface], faces[Dface], faces[Lface]
@@ -539,6 +580,9 @@ func (c *Cube) MoveF(width int) {
 }
 
 func (c *Cube) MoveFPrime(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Fface, width, true)
+	}
 	n := c.Size
 	faces := c.Faces
 	u, r, d, l := faces[Uface], faces[Rface], faces[Dface], faces[Lface]
@@ -573,6 +617,9 @@ func (c *Cube) MoveFPrime(width int) {
 }
 
 func (c *Cube) MoveB(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Bface, width, false)
+	}
 	n := c.Size
 	faces := c.Faces
 	u, r, d, l := faces[Uface], faces[Rface], faces[Dface], faces[Lface]
@@ -605,6 +652,9 @@ func (c *Cube) MoveB(width int) {
 }
 
 func (c *Cube) MoveBPrime(width int) {
+	if c.Stickers != nil {
+		c.turnOrient(Bface, width, true)
+	}
 	n := c.Size
 	faces := c.Faces
 	u, r, d, l := faces[Uface], faces[Rface], faces[Dface], faces[Lface]
@@ -638,107 +688,28 @@ func (c *Cube) MoveBPrime(width int) {
 
 // Moves applies a sequence of moves, stripping parentheses.
 func (c *Cube) Moves(seqs ...string) error {
-	seq := strings.Join(seqs, " ")
-	seq = strings.NewReplacer("(", "", ")", "").Replace(seq)
-	for _, m := range strings.Fields(seq) {
-		if err := c.Move(m); err != nil {
-			return err
-		}
+	alg, err := ParseAlg(strings.Join(seqs, " "))
+	if err != nil {
+		return err
 	}
-	return nil
-}
-
-const (
-	flagWide  = 1 << 3
-	flagRot   = 1 << 4
-	flagSlice = 1 << 5
-	faceMask  = (1 << 3) - 1 // lower 3 bits for face id
-)
-
-var moveMap = map[string]uint8{
-	// normals
-	"U": Uface,
-	"D": Dface,
-	"R": Rface,
-	"L": Lface,
-	"F": Fface,
-	"B": Bface,
-
-	// wides
-	"Uw": Uface | flagWide,
-	"u":  Uface | flagWide,
-	"Dw": Dface | flagWide,
-	"d":  Dface | flagWide,
-	"Rw": Rface | flagWide,
-	"r":  Rface | flagWide,
-	"Lw": Lface | flagWide,
-	"l":  Lface | flagWide,
-	"Fw": Fface | flagWide,
-	"f":  Fface | flagWide,
-	"Bw": Bface | flagWide,
-	"b":  Bface | flagWide,
-
-	// rotations
-	"x": Rface | flagRot,
-	"y": Uface | flagRot,
-	"z": Fface | flagRot,
-
-	// slices
-	"M": Lface | flagSlice,
-	"E": Dface | flagSlice,
-	"S": Fface | flagSlice,
-}
-
-func (c *Cube) parseNotation(notation string) (face, count, width int, isPrime, isSlice bool) {
-	width = 1
-	// leading digit as width
-	if len(notation) > 0 && notation[0] >= '0' && notation[0] <= '9' {
-		width = int(notation[0] - '0')
-		notation = notation[1:]
-	}
-	// prime suffix
-	isPrime = false
-	if n := len(notation); n > 0 && notation[n-1] == '\'' {
-		isPrime = true
-		notation = notation[:n-1]
-	}
-
-	// double suffix
-	count = 1
-	if n := len(notation); n > 0 && notation[n-1] == '2' {
-		count = 2
-		notation = notation[:n-1]
-	}
-
-	// mapping
-	isSlice = false
-	if m, ok := moveMap[notation]; ok {
-		face = int(m & faceMask)
-
-		switch {
-		case m&flagWide != 0:
-			// set width=2 if isWide && width<=1
-			if width < 2 {
-				width = 2
-			}
-
-		case m&flagRot != 0:
-			width = c.Size
-
-		case m&flagSlice != 0:
-			isSlice = true
-		}
+	if err := alg.Validate(c.Size); err != nil {
+		return err
 	}
-
-	return face, count, width, isPrime, isSlice
+	alg.Apply(c)
+	return nil
 }
 
 // Move parses a notation (e.g. "R2'", "u"), then calls the appropriate face-turn.
 func (c *Cube) Move(notation string) error {
-	face, count, width, isPrime, isSlice := c.parseNotation(notation)
-
-	// apply move times times
-	return c.PerformFaceTurn(face, count, width, isPrime, isSlice)
+	m, err := ParseMove(notation)
+	if err != nil {
+		return &ParseError{Token: notation, Err: err}
+	}
+	if err := m.Validate(c.Size); err != nil {
+		return &ParseError{Token: notation, Err: err}
+	}
+	m.Apply(c)
+	return nil
 }
 
 func (c *Cube) PerformFaceTurn(face, count, width int, isPrime bool, isSlice bool) error {
@@ -815,6 +786,22 @@ func (c *Cube) PerformFaceTurn(face, count, width int, isPrime bool, isSlice boo
 	return nil
 }
 
+// turnLayers turns layers lo..hi (counted from 1 at face) by amount
+// clockwise quarter turns. An inner block is the outer hi layers turned one
+// way and the outer lo-1 layers turned back.
+func (c *Cube) turnLayers(face, lo, hi, amount int) {
+	count := amount
+	if count < 0 {
+		count = -count
+	}
+	for range count {
+		c.PerformFaceTurn(face, 1, hi, amount < 0, false)
+		if lo > 1 {
+			c.PerformFaceTurn(face, 1, lo-1, amount > 0, false)
+		}
+	}
+}
+
 // IsSolved returns true if every face of the cube is uniform (all stickers match the face index).
 func (c *Cube) IsSolved() bool {
 	for f := 0; f < 6; f++ {
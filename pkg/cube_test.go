@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -132,6 +133,15 @@ func testFindSolutions(t *testing.T, findSolutions FindSolutionsFunc, maxDepth i
 		}
 	}
 	fmt.Println()
+
+	// a bad move set finds nothing; ParseMoveSet reports where it fails
+	if solutions := findSolutions(c, []string{"R", "Q"}, check, maxDepth, nil); solutions != nil {
+		t.Errorf("findSolutions with move Q: got %v, want nil", solutions)
+	}
+	var pe *ParseError
+	if _, err := ParseMoveSet([]string{"R", "Q"}, 3); !errors.As(err, &pe) || pe.Index != 1 {
+		t.Errorf("ParseMoveSet with move Q: got %v, want a *ParseError at 1", err)
+	}
 }
 
 func TestFindSolutionsIter(t *testing.T) {
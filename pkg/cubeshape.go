@@ -0,0 +1,146 @@
+package pkg
+
+import "sort"
+
+// CubeShapeCase is a Square-1 shape with a shortest way back to cube shape.
+type CubeShapeCase struct {
+	Top, Bottom string // layer shapes, as returned by Square1.Shape
+	// State has the shape, with each layer's pieces starting at unit 0 in
+	// the order of its shape string, so that Solution applies to it.
+	State    *Square1
+	Solution Sq1Alg // the fewest / moves to reach cube shape
+}
+
+// sq1ShapeCode packs the kind of every unit, which is all that decides
+// which moves are legal and what shape they lead to.
+func sq1ShapeCode(s *Square1) uint64 {
+	var code uint64
+	for _, u := range s.Units {
+		code = code<<2 | uint64(sq1Layout[u].kind-'a'+1)&3
+	}
+	return code
+}
+
+// sq1Shape is a node of the shape search: the move that first reached the
+// code from its parent.
+type sq1Shape struct {
+	parent uint64
+	move   Sq1Move
+	dist   int
+}
+
+// CubeShapes returns the 90 shapes the Square-1 can take, with a solution
+// using the fewest / moves, sorted by solution length then shape. Turning
+// the puzzle over swaps its layers, so each shape is listed once, with
+// Top <= Bottom. It runs a breadth-first search over shapes from cube
+// shape, where layer turns are free and every / costs one.
+func CubeShapes() []CubeShapeCase {
+	start := NewSquare1()
+	nodes := map[uint64]sq1Shape{sq1ShapeCode(start): {}}
+	states := map[uint64]*Square1{sq1ShapeCode(start): start}
+	// 0-1 breadth-first search: turns go to the front of the deque
+	queue := []uint64{sq1ShapeCode(start)}
+	for len(queue) > 0 {
+		code := queue[0]
+		queue = queue[1:]
+		s, d := states[code], nodes[code].dist
+		visit := func(t *Square1, m Sq1Move, cost int) {
+			c := sq1ShapeCode(t)
+			if n, ok := nodes[c]; ok && n.dist <= d+cost {
+				return
+			}
+			nodes[c] = sq1Shape{parent: code, move: m, dist: d + cost}
+			states[c] = t
+			if cost == 0 {
+				queue = append([]uint64{c}, queue...)
+			} else {
+				queue = append(queue, c)
+			}
+		}
+		for a := range 12 {
+			for b := range 12 {
+				if a == 0 && b == 0 {
+					continue
+				}
+				m := Sq1Move{Top: sq1Turn(a), Bottom: sq1Turn(b)}
+				t := s.Copy()
+				t.Apply(m)
+				visit(t, m, 0)
+			}
+		}
+		if s.CanSlash() {
+			t := s.Copy()
+			t.Apply(Sq1Move{Slash: true})
+			visit(t, Sq1Move{Slash: true}, 1)
+		}
+	}
+
+	seen := make(map[[2]string]bool)
+	var cases []CubeShapeCase
+	for code, s := range states {
+		top, bottom := s.Shape()
+		if top > bottom || seen[[2]string{top, bottom}] {
+			continue
+		}
+		seen[[2]string{top, bottom}] = true
+		state := s.Copy()
+		state.alignShape(0, top)
+		state.alignShape(12, bottom)
+		code = sq1ShapeCode(state)
+		var sol Sq1Alg
+		for nodes[code].dist > 0 {
+			n := nodes[code]
+			m := n.move
+			if !m.Slash {
+				m = Sq1Move{Top: sq1Turn(-m.Top), Bottom: sq1Turn(-m.Bottom)}
+			}
+			sol = sol.appendTurn(m)
+			code = n.parent
+		}
+		cases = append(cases, CubeShapeCase{Top: top, Bottom: bottom, State: state, Solution: sol})
+	}
+	sort.Slice(cases, func(i, j int) bool {
+		a, b := cases[i], cases[j]
+		if la, lb := a.Solution.Slashes(), b.Solution.Slashes(); la != lb {
+			return la < lb
+		}
+		if a.Top != b.Top {
+			return a.Top < b.Top
+		}
+		return a.Bottom < b.Bottom
+	})
+	return cases
+}
+
+// appendTurn appends m, merging consecutive layer turns.
+func (a Sq1Alg) appendTurn(m Sq1Move) Sq1Alg {
+	if n := len(a); n > 0 && !m.Slash && !a[n-1].Slash {
+		last := a[n-1]
+		a = a[:n-1]
+		m = Sq1Move{Top: sq1Turn(last.Top + m.Top), Bottom: sq1Turn(last.Bottom + m.Bottom)}
+	}
+	if !m.Slash && m.Top == 0 && m.Bottom == 0 {
+		return a
+	}
+	return append(a, m)
+}
+
+// alignShape turns the layer starting at unit base until its pieces read
+// shape from unit 0.
+func (s *Square1) alignShape(base int, shape string) {
+	for range 12 {
+		var pieces []byte
+		for i := range 12 {
+			switch sq1Layout[s.Units[base+i]].kind {
+			case 'e':
+				pieces = append(pieces, 'e')
+			case 'a':
+				pieces = append(pieces, 'c')
+			}
+		}
+		if sq1Layout[s.Units[base]].kind != 'b' && string(pieces) == shape {
+			return
+		}
+		s.turnLayer(base, 1)
+	}
+}
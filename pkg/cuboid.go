@@ -0,0 +1,339 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrBadTurn is returned for a quarter turn of a cuboid layer that is not
+// square, which would jam the puzzle.
+var ErrBadTurn = errors.New("bad turn")
+
+// Cuboid is an X×Y×Z puzzle such as the 2x2x3 or 3x3x2: X stickers from L
+// to R, Y from D to U and Z from B to F. Faces are laid out like those of a
+// Cube (see Display), so U and D are Z rows of X stickers, F and B Y rows
+// of X, and R and L Y rows of Z. Only half turns are allowed on a layer
+// whose face is not square.
+type Cuboid struct {
+	X, Y, Z  int
+	Faces    [6][]byte // views into stickers, one per face
+	stickers []byte
+	buffer   []byte
+	shape    *cuboidShape
+}
+
+// cuboidShape holds the sticker permutation of every move used so far on
+// cuboids of one size, shared by all copies.
+type cuboidShape struct {
+	dims   [3]int
+	mu     sync.Mutex
+	tables map[Move][]int
+	list   atomic.Pointer[cuboidMoves] // moves registered by MoveIndex
+}
+
+var (
+	cuboidShapesMu sync.Mutex
+	cuboidShapes   = make(map[[3]int]*cuboidShape)
+)
+
+// NewCuboid creates a solved X×Y×Z cuboid.
+func NewCuboid(x, y, z int) *Cuboid {
+	key := [3]int{x, y, z}
+	cuboidShapesMu.Lock()
+	shape, ok := cuboidShapes[key]
+	if !ok {
+		shape = &cuboidShape{dims: key, tables: make(map[Move][]int)}
+		cuboidShapes[key] = shape
+	}
+	cuboidShapesMu.Unlock()
+
+	q := &Cuboid{X: x, Y: y, Z: z, shape: shape}
+	total := 0
+	for f := range 6 {
+		rows, cols := q.faceDims(f)
+		total += rows * cols
+	}
+	q.stickers = make([]byte, total)
+	q.buffer = make([]byte, total)
+	q.setFaces()
+	for f := range 6 {
+		for i := range q.Faces[f] {
+			q.Faces[f][i] = byte(f)
+		}
+	}
+	return q
+}
+
+// setFaces points Faces into the sticker array.
+func (q *Cuboid) setFaces() {
+	off := 0
+	for f := range 6 {
+		rows, cols := q.faceDims(f)
+		q.Faces[f] = q.stickers[off : off+rows*cols : off+rows*cols]
+		off += rows * cols
+	}
+}
+
+// Copy returns a deep copy of this cuboid.
+func (q *Cuboid) Copy() *Cuboid {
+	c := &Cuboid{X: q.X, Y: q.Y, Z: q.Z, shape: q.shape}
+	c.stickers = append([]byte(nil), q.stickers...)
+	c.buffer = make([]byte, len(q.stickers))
+	c.setFaces()
+	return c
+}
+
+// faceDims returns the rows and columns of face f.
+func (q *Cuboid) faceDims(f int) (rows, cols int) {
+	switch f {
+	case Uface, Dface:
+		return q.Z, q.X
+	case Fface, Bface:
+		return q.Y, q.X
+	default: // Rface, Lface
+		return q.Y, q.Z
+	}
+}
+
+// depth returns the number of layers that turn about the axis of face f.
+func (q *Cuboid) depth(f int) int {
+	return [3]int{q.X, q.Y, q.Z}[faceAxis[f]]
+}
+
+// quarterTurns reports whether the layers parallel to face f are square, so
+// that they can be turned by a quarter.
+func (q *Cuboid) quarterTurns(f int) bool {
+	rows, cols := q.faceDims(f)
+	return rows == cols
+}
+
+// String describes the cuboid size as X×Z×Y, footprint first, e.g. 2x2x3.
+func (q *Cuboid) String() string {
+	return fmt.Sprintf("%dx%dx%d", q.X, q.Z, q.Y)
+}
+
+// ValidateMove reports whether m can be performed on the cuboid: its layers
+// must exist and a quarter turn needs square layers.
+func (q *Cuboid) ValidateMove(m Move) error {
+	d := q.depth(m.Face)
+	lo, hi := m.layerRange(d)
+	switch {
+	case hi > d || lo < 1 || lo > hi:
+		return fmt.Errorf("%w: %s on a %s cuboid", ErrBadWidth, m, q)
+	case (m.Kind == MoveSlice || m.Kind == MoveWideSlice) && d < 3:
+		return fmt.Errorf("%w: no slice %s on a %s cuboid", ErrBadWidth, m, q)
+	case (m.Amount == 1 || m.Amount == -1) && !q.quarterTurns(m.Face):
+		return fmt.Errorf("%w: %s needs square layers, only half turns are allowed on a %s cuboid", ErrBadTurn, m, q)
+	}
+	return nil
+}
+
+// ValidateAlg reports the first move of a that cannot be performed on the
+// cuboid as a *ParseError.
+func (q *Cuboid) ValidateAlg(a Alg) error {
+	for i, m := range a {
+		if err := q.ValidateMove(m); err != nil {
+			return moveError(i, m, err)
+		}
+	}
+	return nil
+}
+
+// ApplyMove performs m on the cuboid, or fails if it is not a legal move.
+func (q *Cuboid) ApplyMove(m Move) error {
+	t, err := q.table(m)
+	if err != nil {
+		return err
+	}
+	q.applyTable(t)
+	return nil
+}
+
+// Moves parses algs and applies them in order. Nothing is applied if any
+// of them does not parse or has a move that is not legal on the cuboid.
+func (q *Cuboid) Moves(seqs ...string) error {
+	algs := make([]Alg, len(seqs))
+	for i, s := range seqs {
+		alg, err := ParseAlg(s)
+		if err != nil {
+			return err
+		}
+		if err := q.ValidateAlg(alg); err != nil {
+			return err
+		}
+		algs[i] = alg
+	}
+	for _, alg := range algs {
+		for _, m := range alg {
+			q.ApplyMove(m)
+		}
+	}
+	return nil
+}
+
+// applyTable moves every sticker i to t[i].
+func (q *Cuboid) applyTable(t []int) {
+	for i, v := range q.stickers {
+		q.buffer[t[i]] = v
+	}
+	q.stickers, q.buffer = q.buffer, q.stickers
+	q.setFaces()
+}
+
+// IsSolved returns true if every face shows only its own color.
+func (q *Cuboid) IsSolved() bool {
+	for f := range 6 {
+		for _, v := range q.Faces[f] {
+			if v != byte(f) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// table returns the sticker permutation of m, building it on first use.
+func (q *Cuboid) table(m Move) ([]int, error) {
+	if err := q.ValidateMove(m); err != nil {
+		return nil, err
+	}
+	s := q.shape
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.tables[m]; ok {
+		return t, nil
+	}
+	t := q.buildTable(m)
+	s.tables[m] = t
+	return t, nil
+}
+
+// cuboidSticker locates a sticker by the doubled, centred coordinates of its
+// cubie (so that every axis is symmetric about 0) and the face it is on.
+type cuboidSticker struct {
+	pos  [3]int
+	face int
+}
+
+// stickerAt returns the location of sticker i of face f, following the
+// layout of stickerPos.
+func (q *Cuboid) stickerAt(f, i int) cuboidSticker {
+	rows, cols := q.faceDims(f)
+	r, c := i/cols, i%cols
+	x1, y1, z1 := q.X-1, q.Y-1, q.Z-1
+	var x, y, z int
+	switch f {
+	case Uface:
+		x, y, z = c, y1, r
+	case Dface:
+		x, y, z = c, 0, rows-1-r
+	case Fface:
+		x, y, z = c, y1-r, z1
+	case Bface:
+		x, y, z = x1-c, y1-r, 0
+	case Rface:
+		x, y, z = x1, y1-r, z1-c
+	default: // Lface
+		x, y, z = 0, y1-r, c
+	}
+	return cuboidSticker{pos: [3]int{2*x - x1, 2*y - y1, 2*z - z1}, face: f}
+}
+
+// buildTable computes where m sends every sticker by turning the cubies in
+// its layers about the axis of its face.
+func (q *Cuboid) buildTable(m Move) []int {
+	index := make(map[cuboidSticker]int, len(q.stickers))
+	off := 0
+	for f := range 6 {
+		for i := range q.Faces[f] {
+			index[q.stickerAt(f, i)] = off + i
+		}
+		off += len(q.Faces[f])
+	}
+
+	d := q.depth(m.Face)
+	lo, hi := m.layerRange(d)
+	normal := faceNormal[m.Face]
+	axis := faceAxis[m.Face]
+	quarters := (m.Amount%4 + 4) % 4
+
+	t := make([]int, len(q.stickers))
+	off = 0
+	for f := range 6 {
+		for i := range q.Faces[f] {
+			s := q.stickerAt(f, i)
+			// layer 1 is the one on the face, counting inwards
+			layer := (d-1-s.pos[axis]*normal[axis])/2 + 1
+			if layer >= lo && layer <= hi {
+				dir := faceNormal[s.face]
+				for range quarters {
+					s.pos = turnClockwise(normal, s.pos)
+					dir = turnClockwise(normal, dir)
+				}
+				s.face = normalFace(dir)
+			}
+			t[off+i] = index[s]
+		}
+		off += len(q.Faces[f])
+	}
+	return t
+}
+
+// turnClockwise rotates v a quarter turn clockwise as seen looking at the
+// face with outward normal u: v' = u(u·v) - u×v.
+func turnClockwise(u, v [3]int) [3]int {
+	dot := u[0]*v[0] + u[1]*v[1] + u[2]*v[2]
+	cross := [3]int{u[1]*v[2] - u[2]*v[1], u[2]*v[0] - u[0]*v[2], u[0]*v[1] - u[1]*v[0]}
+	return [3]int{u[0]*dot - cross[0], u[1]*dot - cross[1], u[2]*dot - cross[2]}
+}
+
+// normalFace returns the face with outward normal v.
+func normalFace(v [3]int) int {
+	for f, n := range faceNormal {
+		if n == v {
+			return f
+		}
+	}
+	panic("not a face normal")
+}
+
+// Display prints the cuboid net in ASCII using face letters.
+func (q *Cuboid) Display() {
+	q.display("  ", func(v byte) string { return string(colorChar[v]) + " " })
+}
+
+// DisplayColorANSI prints the cuboid net with ANSI-colored stickers.
+func (q *Cuboid) DisplayColorANSI() {
+	q.display(sticker, func(v byte) string { return ansiBg[v] + sticker + reset })
+}
+
+// display prints the net U / L F R B / D, painting each sticker with paint
+// and indenting U and D by the width of L in blank stickers.
+func (q *Cuboid) display(blank string, paint func(byte) string) {
+	indent := strings.Repeat(blank, q.Z)
+	row := func(f, r int) {
+		_, cols := q.faceDims(f)
+		for _, v := range q.Faces[f][r*cols : r*cols+cols] {
+			fmt.Print(paint(v))
+		}
+	}
+	for r := range q.Z {
+		fmt.Print(indent)
+		row(Uface, r)
+		fmt.Println()
+	}
+	for r := range q.Y {
+		for _, f := range []int{Lface, Fface, Rface, Bface} {
+			row(f, r)
+		}
+		fmt.Println()
+	}
+	for r := range q.Z {
+		fmt.Print(indent)
+		row(Dface, r)
+		fmt.Println()
+	}
+}
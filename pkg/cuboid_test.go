@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// cuboidFacelets returns the stickers of q in facelet order.
+func cuboidFacelets(q *Cuboid) string {
+	b := make([]byte, 0, len(q.stickers))
+	for f := range 6 {
+		for _, v := range q.Faces[f] {
+			b = append(b, faceLetters[v])
+		}
+	}
+	return string(b)
+}
+
+func TestCuboidMatchesCube(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	for n := 2; n <= 5; n++ {
+		for range 20 {
+			var alg Alg
+			for _, m := range randomAlg(rng, 20) {
+				// the 2x2 has no slices
+				if n > 2 || !m.isSlice() {
+					alg = append(alg, m)
+				}
+			}
+			c := NewCube(n)
+			alg.Apply(c)
+			q := NewCuboid(n, n, n)
+			for _, m := range alg {
+				if err := q.ApplyMove(m); err != nil {
+					t.Fatalf("%d cuboid %s: %v", n, m, err)
+				}
+			}
+			if got, want := cuboidFacelets(q), c.Facelets(); got != want {
+				t.Fatalf("%dx%dx%d %q:\ngot  %s\nwant %s", n, n, n, alg, got, want)
+			}
+		}
+	}
+}
+
+func TestCuboidTurns(t *testing.T) {
+	q := NewCuboid(2, 3, 2) // 2x2x3
+	if q.String() != "2x2x3" {
+		t.Errorf("String() = %q", q)
+	}
+	for _, ok := range []string{"U", "D'", "u", "R2", "F2", "y", "x2", "E2", "3Uw"} {
+		m, _ := ParseMove(ok)
+		if err := q.ValidateMove(m); err != nil {
+			t.Errorf("%s on 2x2x3: %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"R", "F'", "x", "z'", "M2", "4U", "3R2"} {
+		m, _ := ParseMove(bad)
+		if err := q.ValidateMove(m); err == nil {
+			t.Errorf("%s on 2x2x3: expected error", bad)
+		}
+	}
+	m, _ := ParseMove("R")
+	if err := q.ApplyMove(m); !errors.Is(err, ErrBadTurn) {
+		t.Errorf("R on 2x2x3: got %v, want ErrBadTurn", err)
+	}
+
+	// 3x3x2 allows quarter turns of U and D only
+	q = NewCuboid(3, 2, 3)
+	if err := q.Moves("U R2 U' F2 D"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Moves("R"); err == nil {
+		t.Error("R on 3x3x2: expected error")
+	}
+	if err := q.Moves("U", "R"); err == nil {
+		t.Error("U then R on 3x3x2: expected error")
+	}
+	if err := q.Moves("D' F2 U R2 U'"); err != nil || !q.IsSolved() {
+		t.Errorf("3x3x2 scramble and inverse: solved %v, %v", q.IsSolved(), err)
+	}
+
+	// (R2 U2)3 on a 2x2x3 is not the identity but its repeat is
+	q = NewCuboid(2, 3, 2)
+	q.Moves("(R2 U2)3")
+	c := q.Copy()
+	if q.IsSolved() {
+		t.Error("(R2 U2)3 should not solve a 2x2x3")
+	}
+	q.Moves("(R2 U2)3")
+	if !q.IsSolved() || c.IsSolved() {
+		t.Error("(R2 U2)6 should solve a 2x2x3, leaving the copy alone")
+	}
+}
+
+func TestFindCuboidAlgs(t *testing.T) {
+	q := NewCuboid(2, 3, 2)
+	q.Moves("R2 U F2")
+	moves, _ := ParseAlgStrings([]string{"U", "U'", "U2", "R2", "F2"})
+	sols, err := FindCuboidAlgs(q, moves, (*Cuboid).IsSolved, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sols) != 1 || sols[0].String() != "F2 U' R2" {
+		t.Errorf("solutions %v, want [F2 U' R2]", sols)
+	}
+
+	bad, _ := ParseAlgStrings([]string{"U", "R"})
+	if _, err := FindCuboidAlgs(q, bad, (*Cuboid).IsSolved, 3); !errors.Is(err, ErrBadTurn) {
+		t.Errorf("move set with R: got %v, want ErrBadTurn", err)
+	}
+}
@@ -0,0 +1,143 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StickerDiff is a sticker that shows a different color on two cubes.
+type StickerDiff struct {
+	Face  int
+	Index int
+	From  byte // color on the first cube
+	To    byte // color on the second cube
+}
+
+// CubeDiff lists what differs between two cubes of the same size: every
+// sticker, and the corner and (on odd cubes) middle edge positions with at
+// least one differing sticker.
+type CubeDiff struct {
+	Stickers []StickerDiff
+	Corners  []Corner
+	Edges    []Edge
+}
+
+// Diff compares c with o sticker by sticker. The states need not be valid.
+func (c *Cube) Diff(o *Cube) (*CubeDiff, error) {
+	if c.Size != o.Size {
+		return nil, fmt.Errorf("cannot diff a %dx%d cube with a %dx%d cube", c.Size, c.Size, o.Size, o.Size)
+	}
+	n := c.Size
+	d := &CubeDiff{}
+	for f := range 6 {
+		for i, v := range c.Faces[f] {
+			if w := o.Faces[f][i]; v != w {
+				d.Stickers = append(d.Stickers, StickerDiff{Face: f, Index: i, From: v, To: w})
+			}
+		}
+	}
+
+	for pos := range cornerFaces {
+		for k, f := range cornerFaces[pos] {
+			i := cornerFacelet(n, Corner(pos), k)
+			if c.Faces[f][i] != o.Faces[f][i] {
+				d.Corners = append(d.Corners, Corner(pos))
+				break
+			}
+		}
+	}
+	if n%2 == 1 {
+		for pos := range edgeFaces {
+			for k, f := range edgeFaces[pos] {
+				i := edgeFacelet(n, Edge(pos), k)
+				if c.Faces[f][i] != o.Faces[f][i] {
+					d.Edges = append(d.Edges, Edge(pos))
+					break
+				}
+			}
+		}
+	}
+	return d, nil
+}
+
+// Empty reports whether the cubes were identical.
+func (d *CubeDiff) Empty() bool {
+	return len(d.Stickers) == 0
+}
+
+// String lists the differing pieces and stickers, e.g.
+//
+//	corners: URF UBR
+//	stickers: U9 R->F, F3 F->R
+func (d *CubeDiff) String() string {
+	if d.Empty() {
+		return "no differences"
+	}
+	var lines []string
+	if len(d.Corners) > 0 {
+		names := make([]string, len(d.Corners))
+		for i, c := range d.Corners {
+			names[i] = c.String()
+		}
+		lines = append(lines, "corners: "+strings.Join(names, " "))
+	}
+	if len(d.Edges) > 0 {
+		names := make([]string, len(d.Edges))
+		for i, e := range d.Edges {
+			names[i] = e.String()
+		}
+		lines = append(lines, "edges: "+strings.Join(names, " "))
+	}
+	stickers := make([]string, len(d.Stickers))
+	for i, s := range d.Stickers {
+		stickers[i] = fmt.Sprintf("%c%d %c->%c", faceLetters[s.Face], s.Index+1, faceLetters[s.From], faceLetters[s.To])
+	}
+	lines = append(lines, "stickers: "+strings.Join(stickers, ", "))
+	return strings.Join(lines, "\n")
+}
+
+// ansiDim is the background of stickers that match in DisplayDiffANSI.
+const ansiDim = "\x1b[48;5;236m"
+
+// DisplayDiffANSI prints c as DisplayColorANSI does, but with the stickers
+// that match o dimmed so that only the differing ones show their color.
+func (c *Cube) DisplayDiffANSI(o *Cube) error {
+	if c.Size != o.Size {
+		return fmt.Errorf("cannot diff a %dx%d cube with a %dx%d cube", c.Size, c.Size, o.Size, o.Size)
+	}
+	n := c.Size
+	indent := strings.Repeat("⠀", n*2)
+	// helper to paint a row of stickers of face f
+	paintRow := func(f, r int) {
+		for i := r * n; i < r*n+n; i++ {
+			bg := ansiBg[int(c.Faces[f][i])]
+			if c.Faces[f][i] == o.Faces[f][i] {
+				bg = ansiDim
+			}
+			fmt.Print(bg + sticker + reset)
+		}
+	}
+
+	// U face
+	for r := 0; r < n; r++ {
+		fmt.Print(indent)
+		paintRow(Uface, r)
+		fmt.Println()
+	}
+
+	// middle L-F-R-B
+	for r := 0; r < n; r++ {
+		for _, f := range []int{Lface, Fface, Rface, Bface} {
+			paintRow(f, r)
+		}
+		fmt.Println()
+	}
+
+	// D face
+	for r := 0; r < n; r++ {
+		fmt.Print(indent)
+		paintRow(Dface, r)
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package pkg
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	a, b := NewCube(3), NewCube(3)
+	d, err := a.Diff(b)
+	if err != nil || !d.Empty() || d.String() != "no differences" {
+		t.Fatalf("solved vs solved: %v, %v", d, err)
+	}
+
+	// a T-perm swaps two corners and two edges; their shared U and R colors
+	// leave only 6 stickers different
+	b.Moves("R U R' U' R' F R2 U' R' U' R U R' F'")
+	d, err = a.Diff(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Stickers) != 6 {
+		t.Errorf("T-perm: %d differing stickers, want 6", len(d.Stickers))
+	}
+	if len(d.Corners) != 2 || d.Corners[0] != URF || d.Corners[1] != UBR {
+		t.Errorf("T-perm: corners %v, want URF UBR", d.Corners)
+	}
+	if len(d.Edges) != 2 || d.Edges[0] != UR || d.Edges[1] != UL {
+		t.Errorf("T-perm: edges %v, want UR UL", d.Edges)
+	}
+	for _, s := range d.Stickers {
+		if a.Faces[s.Face][s.Index] != s.From || b.Faces[s.Face][s.Index] != s.To {
+			t.Errorf("sticker %+v does not match the cubes", s)
+		}
+	}
+
+	c := NewCube(2)
+	c.Moves("U")
+	d, _ = NewCube(2).Diff(c)
+	if len(d.Corners) != 4 || len(d.Edges) != 0 || len(d.Stickers) != 8 {
+		t.Errorf("2x2 U: %d corners, %d edges, %d stickers; want 4, 0, 8", len(d.Corners), len(d.Edges), len(d.Stickers))
+	}
+
+	if _, err := a.Diff(c); err == nil {
+		t.Error("diffing cubes of different sizes should fail")
+	}
+}
@@ -0,0 +1,82 @@
+package pkg
+
+// DistanceTable is a God's-algorithm table: the distance from solved of
+// every state reachable with a move set, keyed by a code that packs a state
+// into a number (see Skewb.Code).
+type DistanceTable[P Puzzle[P]] struct {
+	// Counts is the number of states at each distance; the last index is
+	// God's number for the move set.
+	Counts []int
+
+	moves []int
+	code  func(P) uint64
+	dist  map[uint64]uint8
+}
+
+// NewDistanceTable builds the table by a breadth-first search from solved.
+// moves should contain the inverse of each of its moves.
+func NewDistanceTable[P Puzzle[P]](solved P, moves []int, code func(P) uint64) *DistanceTable[P] {
+	t := &DistanceTable[P]{moves: moves, code: code, dist: map[uint64]uint8{code(solved): 0}}
+	frontier := []P{solved.Copy()}
+	for len(frontier) > 0 {
+		t.Counts = append(t.Counts, len(frontier))
+		d := uint8(len(t.Counts))
+		var next []P
+		for _, p := range frontier {
+			for _, m := range moves {
+				p.Apply(m)
+				if k := code(p); !t.has(k) {
+					t.dist[k] = d
+					next = append(next, p.Copy())
+				}
+				p.Apply(p.InverseMove(m))
+			}
+		}
+		frontier = next
+	}
+	return t
+}
+
+func (t *DistanceTable[P]) has(k uint64) bool {
+	_, ok := t.dist[k]
+	return ok
+}
+
+// Len returns the number of states in the table.
+func (t *DistanceTable[P]) Len() int { return len(t.dist) }
+
+// Distance returns the number of moves needed to solve p, or false if p
+// cannot be reached with the table's moves.
+func (t *DistanceTable[P]) Distance(p P) (int, bool) {
+	d, ok := t.dist[t.code(p)]
+	return int(d), ok
+}
+
+// Solve returns an optimal solution for p using the given moves, which must
+// reach the same states as the table's, taking the first move at each step
+// that brings p closer to solved. It returns false if p is not in the
+// table.
+func (t *DistanceTable[P]) Solve(p P, moves []int) ([]int, bool) {
+	d, ok := t.Distance(p)
+	if !ok {
+		return nil, false
+	}
+	p = p.Copy()
+	var sol []int
+	for d > 0 {
+		found := false
+		for _, m := range moves {
+			p.Apply(m)
+			if e, ok := t.Distance(p); ok && e == d-1 {
+				sol = append(sol, m)
+				d, found = e, true
+				break
+			}
+			p.Apply(p.InverseMove(m))
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return sol, true
+}
@@ -0,0 +1,49 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Facelets returns the state as a facelet string in Kociemba's order,
+// generalised to n×n: the faces U, R, F, D, L, B in turn, each read row by
+// row as laid out in the net printed by Display (U with its back edge on
+// top, D with its front edge on top, L R F B upright), one letter per
+// sticker naming the face whose color it shows. A solved 3x3 is
+// "UUUUUUUUURRRRRRRRRFFFFFFFFFDDDDDDDDDLLLLLLLLLBBBBBBBBB".
+func (c *Cube) Facelets() string {
+	var b strings.Builder
+	b.Grow(6 * c.Size * c.Size)
+	for f := range 6 {
+		for _, v := range c.Faces[f] {
+			b.WriteByte(faceLetters[v])
+		}
+	}
+	return b.String()
+}
+
+// ParseFacelets builds a cube from a facelet string as returned by
+// Facelets. The size is taken from the length, which must be 6n² for some
+// n ≥ 2; whitespace is ignored. The state is not checked for solvability;
+// call Validate for that.
+func ParseFacelets(s string) (*Cube, error) {
+	s = strings.Join(strings.Fields(s), "")
+	n := int(math.Round(math.Sqrt(float64(len(s)) / 6)))
+	if n < 2 || 6*n*n != len(s) {
+		return nil, fmt.Errorf("facelet string has %d stickers, want 6n² for some n ≥ 2", len(s))
+	}
+
+	c := NewCube(n)
+	for f := range 6 {
+		for i := range c.Faces[f] {
+			k := f*n*n + i
+			col := strings.IndexByte(faceLetters, s[k])
+			if col < 0 {
+				return nil, fmt.Errorf("facelet %d: invalid color %q, want one of %s", k+1, s[k], faceLetters)
+			}
+			c.Faces[f][i] = byte(col)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,59 @@
+package pkg
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestFaceletsKnownStates(t *testing.T) {
+	tests := []struct{ moves, want string }{
+		{"", "UUUUUUUUURRRRRRRRRFFFFFFFFFDDDDDDDDDLLLLLLLLLBBBBBBBBB"},
+		{"R", "UUFUUFUUFRRRRRRRRRFFDFFDFFDDDBDDBDDBLLLLLLLLLUBBUBBUBB"},
+		{"U", "UUUUUUUUUBBBRRRRRRRRRFFFFFFDDDDDDDDDFFFLLLLLLLLLBBBBBB"},
+		{"F", "UUUUUULLLURRURRURRFFFFFFFFFRRRDDDDDDLLDLLDLLDBBBBBBBBB"},
+	}
+	for _, tt := range tests {
+		c := NewCube(3)
+		c.Moves(tt.moves)
+		if got := c.Facelets(); got != tt.want {
+			t.Errorf("%q: Facelets() = %s, want %s", tt.moves, got, tt.want)
+		}
+	}
+}
+
+func TestFaceletsRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	for n := 2; n <= 7; n++ {
+		for range 10 {
+			c := NewCube(n)
+			randomAlg(rng, 30).Apply(c)
+
+			s := c.Facelets()
+			if len(s) != 6*n*n {
+				t.Fatalf("%dx%d: Facelets() has length %d", n, n, len(s))
+			}
+			for f := range 6 {
+				for i, v := range c.Faces[f] {
+					if s[f*n*n+i] != faceLetters[v] {
+						t.Fatalf("%dx%d: facelet %d does not match Faces[%d][%d]", n, n, f*n*n+i, f, i)
+					}
+				}
+			}
+
+			back, err := ParseFacelets(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back.Size != n || !sameState(back, c) {
+				t.Fatalf("%dx%d: ParseFacelets(Facelets()) does not round-trip", n, n)
+			}
+		}
+	}
+
+	for _, bad := range []string{"", "UUUU", strings.Repeat("U", 53), strings.Repeat("X", 24)} {
+		if _, err := ParseFacelets(bad); err == nil {
+			t.Errorf("ParseFacelets(%q): expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package pkg
+
+// stickerPos returns the cubie coordinates (x from L to R, y from D to U,
+// z from B to F, each in 0..n-1) of sticker i on face f, following the net
+// used by Display.
+func stickerPos(n, f, i int) (x, y, z int) {
+	r, c, n1 := i/n, i%n, n-1
+	switch f {
+	case Uface:
+		return c, n1, r
+	case Dface:
+		return c, 0, n1 - r
+	case Fface:
+		return c, n1 - r, n1
+	case Bface:
+		return n1 - c, n1 - r, 0
+	case Rface:
+		return n1, n1 - r, n1 - c
+	default: // Lface
+		return 0, n1 - r, c
+	}
+}
+
+// stickerIndex is the inverse of stickerPos for a sticker on face f.
+func stickerIndex(n, f, x, y, z int) int {
+	n1 := n - 1
+	switch f {
+	case Uface:
+		return z*n + x
+	case Dface:
+		return (n1-z)*n + x
+	case Fface:
+		return (n1-y)*n + x
+	case Bface:
+		return (n1-y)*n + n1 - x
+	case Rface:
+		return (n1-y)*n + n1 - z
+	default: // Lface
+		return (n1-y)*n + z
+	}
+}
+
+// faceNormal is the outward unit vector of each face in cubie coordinates.
+var faceNormal = [6][3]int{
+	Uface: {0, 1, 0},
+	Dface: {0, -1, 0},
+	Rface: {1, 0, 0},
+	Lface: {-1, 0, 0},
+	Fface: {0, 0, 1},
+	Bface: {0, 0, -1},
+}
+
+// det3 returns the determinant of the matrix with rows a, b and c.
+func det3(a, b, c [3]int) int {
+	return a[0]*(b[1]*c[2]-b[2]*c[1]) -
+		a[1]*(b[0]*c[2]-b[2]*c[0]) +
+		a[2]*(b[0]*c[1]-b[1]*c[0])
+}
+
+// onFace reports whether the cubie at (x, y, z) of an n×n cube touches
+// face f.
+func onFace(n, f, x, y, z int) bool {
+	pos, normal := [3]int{x, y, z}, faceNormal[f]
+	for a, d := range normal {
+		switch d {
+		case 1:
+			return pos[a] == n-1
+		case -1:
+			return pos[a] == 0
+		}
+	}
+	return false
+}
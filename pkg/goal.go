@@ -0,0 +1,345 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// goal is a compiled goal expression: a mask that must match and any checks
+// that cannot be written as a mask. A nil mask requires nothing.
+type goal struct {
+	mask   *Mask
+	checks []CheckFunc
+}
+
+// and combines two goals, intersecting their masks so that a conjunction of
+// sticker predicates stays a single mask check.
+func (g goal) and(o goal) goal {
+	out := goal{mask: g.mask, checks: append(append([]CheckFunc{}, g.checks...), o.checks...)}
+	switch {
+	case out.mask == nil:
+		out.mask = o.mask
+	case o.mask != nil:
+		m := NewMask(g.mask.Size)
+		for f := range m.Faces {
+			for i := range m.Faces[f] {
+				m.Faces[f][i] = g.mask.Faces[f][i] & o.mask.Faces[f][i]
+			}
+		}
+		out.mask = m
+	}
+	return out
+}
+
+// checkFunc returns the goal as a single CheckFunc.
+func (g goal) checkFunc() CheckFunc {
+	checks := g.checks
+	if g.mask != nil {
+		checks = append([]CheckFunc{g.mask.Matches}, checks...)
+	}
+	switch len(checks) {
+	case 0:
+		return func(*Cube) bool { return true }
+	case 1:
+		return checks[0]
+	}
+	return func(c *Cube) bool {
+		for _, check := range checks {
+			if !check(c) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// goalParser is a recursive-descent parser over goal tokens that compiles
+// as it parses, for an n×n cube.
+type goalParser struct {
+	toks []string
+	pos  int
+	n    int
+}
+
+// lexGoal splits a goal expression into names, brackets and operators.
+func lexGoal(s string) ([]string, error) {
+	var toks []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '!':
+			toks = append(toks, s[i:i+1])
+			i++
+		case strings.HasPrefix(s[i:], "&&") || strings.HasPrefix(s[i:], "||"):
+			toks = append(toks, s[i:i+2])
+			i += 2
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9':
+			j := i
+			for j < len(s) && (s[j] == '_' || s[j] >= 'a' && s[j] <= 'z' || s[j] >= 'A' && s[j] <= 'Z' || s[j] >= '0' && s[j] <= '9') {
+				j++
+			}
+			toks = append(toks, s[i:j])
+			i = j
+		default:
+			return nil, &ParseError{Index: len(toks), Token: s[i : i+1], Err: fmt.Errorf("%w: unexpected character", ErrSyntax)}
+		}
+	}
+	return toks, nil
+}
+
+func (p *goalParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *goalParser) errorf(err error, format string, args ...any) error {
+	return &ParseError{Index: p.pos, Token: p.peek(), Err: fmt.Errorf("%w: "+format, append([]any{err}, args...)...)}
+}
+
+// expect consumes tok or fails.
+func (p *goalParser) expect(tok string) error {
+	if p.peek() != tok {
+		return p.errorf(ErrSyntax, "expected %s", tok)
+	}
+	p.pos++
+	return nil
+}
+
+// or parses a || b || ...
+func (p *goalParser) or() (goal, error) {
+	g, err := p.and()
+	if err != nil {
+		return g, err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		o, err := p.and()
+		if err != nil {
+			return o, err
+		}
+		a, b := g.checkFunc(), o.checkFunc()
+		g = goal{checks: []CheckFunc{func(c *Cube) bool { return a(c) || b(c) }}}
+	}
+	return g, nil
+}
+
+// and parses a && b && ...
+func (p *goalParser) and() (goal, error) {
+	g, err := p.unary()
+	if err != nil {
+		return g, err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		o, err := p.unary()
+		if err != nil {
+			return o, err
+		}
+		g = g.and(o)
+	}
+	return g, nil
+}
+
+// unary parses a negation, a parenthesised expression or a predicate.
+func (p *goalParser) unary() (goal, error) {
+	switch p.peek() {
+	case "!":
+		p.pos++
+		g, err := p.unary()
+		if err != nil {
+			return g, err
+		}
+		check := g.checkFunc()
+		return goal{checks: []CheckFunc{func(c *Cube) bool { return !check(c) }}}, nil
+	case "(":
+		p.pos++
+		g, err := p.or()
+		if err != nil {
+			return g, err
+		}
+		return g, p.expect(")")
+	}
+	return p.predicate()
+}
+
+// stickerGoal returns a goal allowing colors(f) on every sticker (f, x, y, z)
+// that keep selects. It fails if keep selects nothing on this cube size.
+func (p *goalParser) stickerGoal(keep func(f, x, y, z int) bool, colors func(f int) uint8) (goal, error) {
+	m, found := NewMask(p.n), false
+	for f := range m.Faces {
+		for i := range m.Faces[f] {
+			if x, y, z := stickerPos(p.n, f, i); keep(f, x, y, z) {
+				m.Faces[f][i], found = colors(f), true
+			}
+		}
+	}
+	if !found {
+		return goal{}, fmt.Errorf("no such stickers on a %dx%d cube", p.n, p.n)
+	}
+	return goal{mask: m}, nil
+}
+
+// centersTracked is the centers_oriented predicate. It fails on a cube
+// that does not track its stickers, which cannot tell.
+func centersTracked(c *Cube) bool {
+	return c.Stickers != nil && c.CentersOriented()
+}
+
+// GoalTracksStickers reports whether a goal expression uses
+// centers_oriented, which only holds on a cube that tracks its stickers
+// (see Cube.TrackOrientation).
+func GoalTracksStickers(expr string) bool {
+	toks, _ := lexGoal(expr)
+	for _, tok := range toks {
+		if tok == "centers_oriented" {
+			return true
+		}
+	}
+	return false
+}
+
+// predicate parses a built-in predicate:
+//
+//	solved, solved_up_to_rotation, centers_oriented, oriented(F),
+//	layer(F) solved, face(F) solved, center(F) solved,
+//	corner(UFR) solved, edge(UF) solved
+func (p *goalParser) predicate() (goal, error) {
+	name := p.peek()
+	start := p.pos
+	p.pos++
+	n := p.n
+	solved := func(f int) uint8 { return 1 << f }
+
+	switch name {
+	case "solved":
+		return goal{mask: MaskOf(NewCube(n))}, nil
+	case "solved_up_to_rotation":
+		return goal{checks: []CheckFunc{(*Cube).IsSolvedUpToRotation}}, nil
+	case "centers_oriented":
+		return goal{checks: []CheckFunc{centersTracked}}, nil
+	case "oriented", "layer", "face", "center", "corner", "edge":
+	default:
+		p.pos = start
+		return goal{}, p.errorf(ErrSyntax, "unknown predicate")
+	}
+
+	if err := p.expect("("); err != nil {
+		return goal{}, err
+	}
+	arg := strings.ToUpper(p.peek())
+	argPos := p.pos
+	p.pos++
+	if err := p.expect(")"); err != nil {
+		return goal{}, err
+	}
+	if name != "oriented" {
+		if p.peek() != "solved" {
+			return goal{}, p.errorf(ErrSyntax, "expected solved after %s(%s)", name, arg)
+		}
+		p.pos++
+	}
+
+	var g goal
+	var err error
+	switch name {
+	case "corner", "edge":
+		var faces []int
+		if name == "corner" {
+			var c Corner
+			if c, err = ParseCorner(arg); err == nil {
+				faces = cornerFaces[c][:]
+			}
+		} else {
+			var e Edge
+			if e, err = ParseEdge(arg); err == nil {
+				faces = edgeFaces[e][:]
+			}
+		}
+		if err != nil {
+			break
+		}
+		// the stickers of cubies touching exactly the named faces
+		g, err = p.stickerGoal(func(f, x, y, z int) bool {
+			touching := 0
+			for h := range 6 {
+				if onFace(n, h, x, y, z) {
+					touching++
+				}
+			}
+			for _, h := range faces {
+				if !onFace(n, h, x, y, z) {
+					return false
+				}
+			}
+			return touching == len(faces)
+		}, solved)
+
+	default:
+		face := -1
+		if len(arg) == 1 {
+			face = strings.IndexByte(faceLetters, arg[0])
+		}
+		if face < 0 {
+			err = fmt.Errorf("invalid face %q, want one of %s", arg, faceLetters)
+			break
+		}
+		switch name {
+		case "oriented":
+			g, err = p.stickerGoal(func(f, x, y, z int) bool { return f == face },
+				func(int) uint8 { return 1<<face | 1<<oppositeFace[face] })
+		case "layer":
+			g, err = p.stickerGoal(func(f, x, y, z int) bool { return onFace(n, face, x, y, z) }, solved)
+		case "face":
+			g, err = p.stickerGoal(func(f, x, y, z int) bool { return f == face }, solved)
+		case "center":
+			g, err = p.stickerGoal(func(f, x, y, z int) bool {
+				for h := range 6 {
+					if h != f && onFace(n, h, x, y, z) {
+						return false
+					}
+				}
+				return f == face
+			}, solved)
+		}
+	}
+	if err != nil {
+		return goal{}, &ParseError{Index: argPos, Token: arg, Err: err}
+	}
+	return g, nil
+}
+
+// CompileGoal compiles a goal expression for an n×n cube into a CheckFunc.
+// Goals combine predicates with &&, || and ! and parentheses, e.g.
+//
+//	layer(D) solved && oriented(U) && corner(UFR) solved
+//
+// The predicates are solved, solved_up_to_rotation, centers_oriented
+// (every center sticker home and upright; false on a cube that does not
+// track its stickers, see GoalTracksStickers), oriented(F) (face F shows
+// only its own or the opposite color), and layer(F), face(F), center(F),
+// corner(UFR) and edge(UF), each followed by solved. Sticker predicates
+// joined by && are merged into one mask, so a plain conjunction costs a
+// single pass over the stickers. Errors are *ParseError values indexing
+// the tokens of the expression.
+func CompileGoal(expr string, n int) (CheckFunc, error) {
+	toks, err := lexGoal(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &goalParser{toks: toks, n: n}
+	if len(toks) == 0 {
+		return nil, p.errorf(ErrSyntax, "empty goal")
+	}
+	g, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, p.errorf(ErrSyntax, "unexpected %s", p.peek())
+	}
+	return g.checkFunc(), nil
+}
@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompileGoal(t *testing.T) {
+	tests := []struct {
+		n    int
+		goal string
+		alg  string
+		want bool
+	}{
+		{3, "solved", "", true},
+		{3, "solved", "R", false},
+		{3, "layer(D) solved && oriented(U)", "R U R' U' R' F R2 U' R' U' R U R' F'", true},
+		{3, "layer(D) solved && oriented(U)", "R U R' U R U2 R'", false},
+		{3, "layer(D) solved && oriented(U) && corner(UFR) solved", "R U R' U' R' F R2 U' R' U' R U R' F'", false},
+		{3, "layer(D) solved && oriented(U) && corner(UFR) solved", "R2 U R U R' U' R' U' R' U R'", true},
+		{3, "layer(D) solved && !edge(UF) solved", "R2 U R U R' U' R' U' R' U R'", true},
+		{3, "face(U) solved", "R2 L2", false},
+		{3, "center(U) solved && center(D) solved", "R2 L2 F2 B2", true},
+		{3, "center(U) solved", "M", false},
+		{3, "corner(DFR) solved || corner(DLF) solved", "R", true},
+		{3, "corner(DFR) solved || corner(DLF) solved", "R L'", false},
+		{3, "!(layer(U) solved)", "R", true},
+		{2, "solved_up_to_rotation", "R L'", true},
+		{2, "solved_up_to_rotation && !solved", "", false},
+		{2, "layer(D) solved", "U R2 U' R2", false},
+		{2, "layer(D) solved", "U2 F R U R' U' F'", true},
+		{4, "layer(D) solved && center(U) solved", "Uw2 Rw U Rw'", false},
+		{4, "center(F) solved && edge(DF) solved", "R U R'", true},
+	}
+	for _, tt := range tests {
+		check, err := CompileGoal(tt.goal, tt.n)
+		if err != nil {
+			t.Errorf("CompileGoal(%q): %v", tt.goal, err)
+			continue
+		}
+		c := NewCube(tt.n)
+		if err := c.Moves(tt.alg); err != nil {
+			t.Fatal(err)
+		}
+		if got := check(c); got != tt.want {
+			t.Errorf("%dx%d %q after %q = %v, want %v", tt.n, tt.n, tt.goal, tt.alg, got, tt.want)
+		}
+	}
+}
+
+func TestCompileGoalErrors(t *testing.T) {
+	for _, tt := range []struct {
+		n      int
+		goal   string
+		syntax bool
+	}{
+		{3, "", true},
+		{3, "layer(D)", true},
+		{3, "layer(D) solved &&", true},
+		{3, "layer(D solved", true},
+		{3, "(solved", true},
+		{3, "solved solved", true},
+		{3, "solve", true},
+		{3, "solved & solved", true},
+		{3, "layer(X) solved", false},
+		{3, "corner(UF) solved", false},
+		{2, "edge(UF) solved", false},
+		{2, "center(U) solved", false},
+	} {
+		_, err := CompileGoal(tt.goal, tt.n)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("CompileGoal(%q): got %v, want a *ParseError", tt.goal, err)
+			continue
+		}
+		if errors.Is(err, ErrSyntax) != tt.syntax {
+			t.Errorf("CompileGoal(%q): %v, syntax error = %v", tt.goal, err, tt.syntax)
+		}
+	}
+}
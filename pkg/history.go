@@ -0,0 +1,104 @@
+package pkg
+
+// History wraps a cube and records the moves applied through it, with undo
+// and redo. The cube itself is unchanged, so the search code keeps turning
+// *Cube directly at no extra cost.
+type History struct {
+	Cube   *Cube
+	done   Alg // applied moves, oldest first
+	undone Alg // undone moves, the next one to redo last
+}
+
+// Snapshot is a saved cube state and history, see History.Snapshot.
+type Snapshot struct {
+	cube         *Cube
+	done, undone Alg
+}
+
+// NewHistory starts an empty history on c.
+func NewHistory(c *Cube) *History {
+	return &History{Cube: c}
+}
+
+// Apply performs m on the cube and records it, dropping any moves that
+// could have been redone.
+func (h *History) Apply(m Move) error {
+	if err := m.Validate(h.Cube.Size); err != nil {
+		return moveError(0, m, err)
+	}
+	m.Apply(h.Cube)
+	h.done = append(h.done, m)
+	h.undone = h.undone[:0]
+	return nil
+}
+
+// Moves parses an alg and applies it move by move. Nothing is applied if it
+// does not parse or does not fit the cube.
+func (h *History) Moves(s string) error {
+	alg, err := ParseAlg(s)
+	if err != nil {
+		return err
+	}
+	if err := alg.Validate(h.Cube.Size); err != nil {
+		return err
+	}
+	for _, m := range alg {
+		h.Apply(m)
+	}
+	return nil
+}
+
+// Undo reverts the last applied move and returns it, or false if there is
+// nothing to undo.
+func (h *History) Undo() (Move, bool) {
+	if len(h.done) == 0 {
+		return Move{}, false
+	}
+	m := h.done[len(h.done)-1]
+	h.done = h.done[:len(h.done)-1]
+	m.Inverse().Apply(h.Cube)
+	h.undone = append(h.undone, m)
+	return m, true
+}
+
+// Redo reapplies the last undone move and returns it, or false if there is
+// nothing to redo.
+func (h *History) Redo() (Move, bool) {
+	if len(h.undone) == 0 {
+		return Move{}, false
+	}
+	m := h.undone[len(h.undone)-1]
+	h.undone = h.undone[:len(h.undone)-1]
+	m.Apply(h.Cube)
+	h.done = append(h.done, m)
+	return m, true
+}
+
+// Sequence returns the moves applied so far, as performed.
+func (h *History) Sequence() Alg {
+	return append(Alg{}, h.done...)
+}
+
+// Simplified returns the moves applied so far with cancellations merged,
+// a reconstruction of how the cube got to its state.
+func (h *History) Simplified() Alg {
+	return h.Sequence().Simplify()
+}
+
+// Snapshot saves the cube state and history so Restore can return to it.
+func (h *History) Snapshot() Snapshot {
+	return Snapshot{
+		cube:   h.Cube.Copy(),
+		done:   append(Alg{}, h.done...),
+		undone: append(Alg{}, h.undone...),
+	}
+}
+
+// Restore returns the cube and history to a snapshot. The cube is updated
+// in place, so other references to it see the restored state, tracked
+// stickers included.
+func (h *History) Restore(s Snapshot) {
+	*h.Cube = *s.cube.Copy()
+	h.done = append(h.done[:0], s.done...)
+	h.undone = append(h.undone[:0], s.undone...)
+}
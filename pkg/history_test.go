@@ -0,0 +1,77 @@
+package pkg
+
+import "testing"
+
+func TestHistory(t *testing.T) {
+	h := NewHistory(NewCube(3))
+	if err := h.Moves("R U R' U'"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := h.Redo(); ok {
+		t.Error("nothing should be redoable after applying moves")
+	}
+
+	snap := h.Snapshot()
+	for _, want := range []string{"U'", "R'"} {
+		if m, ok := h.Undo(); !ok || m.String() != want {
+			t.Errorf("Undo() = %s, %v; want %s", m, ok, want)
+		}
+	}
+	want := NewCube(3)
+	want.Moves("R U")
+	if !h.Cube.Equal(want) || h.Sequence().String() != "R U" {
+		t.Errorf("after two undos: sequence %q", h.Sequence())
+	}
+	if m, ok := h.Redo(); !ok || m.String() != "R'" {
+		t.Errorf("Redo() = %s, %v; want R'", m, ok)
+	}
+
+	// a new move drops the remaining redo
+	h.Moves("U2 U")
+	if _, ok := h.Redo(); ok {
+		t.Error("a new move should clear the redo stack")
+	}
+	if got := h.Sequence().String(); got != "R U R' U2 U" {
+		t.Errorf("Sequence() = %q", got)
+	}
+	if got := h.Simplified().String(); got != "R U R' U'" {
+		t.Errorf("Simplified() = %q, want R U R' U'", got)
+	}
+
+	h.Restore(snap)
+	if got := h.Sequence().String(); got != "R U R' U'" {
+		t.Errorf("after Restore: sequence %q", got)
+	}
+	want.Moves("R' U'")
+	if !h.Cube.Equal(want) {
+		t.Error("after Restore: wrong cube state")
+	}
+
+	for range 4 {
+		h.Undo()
+	}
+	if _, ok := h.Undo(); ok || !h.Cube.IsSolved() {
+		t.Error("undoing everything should give a solved cube and nothing more to undo")
+	}
+
+	if err := h.Moves("R 4R"); err == nil || len(h.Sequence()) != 0 {
+		t.Error("a move that does not fit the cube should fail without applying anything")
+	}
+}
+
+func TestHistoryRestoreTracked(t *testing.T) {
+	c := NewCube(3)
+	c.TrackOrientation()
+	h := NewHistory(c)
+	snap := h.Snapshot()
+
+	// the stickers come back solved but the R and U centers have turned
+	h.Moves("(R U)105")
+	if !c.IsSolved() || c.IsSolvedOriented() {
+		t.Fatal("(R U)105 should turn the centers")
+	}
+	h.Restore(snap)
+	if !c.IsSolvedOriented() || len(h.Sequence()) != 0 {
+		t.Error("Restore should return the tracked stickers to the snapshot")
+	}
+}
@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"slices"
+)
+
+// CubeRotations lists the 24 whole-cube rotations, starting with the
+// identity: each of the six faces brought to U, combined with the four
+// y rotations.
+var CubeRotations = func() [24]Alg {
+	var rots [24]Alg
+	i := 0
+	for _, top := range []string{"", "x", "x2", "x'", "z", "z'"} {
+		for _, y := range []string{"", "y", "y2", "y'"} {
+			alg, err := ParseAlg(top + " " + y)
+			if err != nil {
+				panic(err)
+			}
+			rots[i] = alg
+			i++
+		}
+	}
+	return rots
+}()
+
+// Equal reports whether two cubes have the same size and stickers, and
+// either both track the same stickers or neither tracks them, so that
+// equal cubes have equal keys and hashes.
+func (c *Cube) Equal(o *Cube) bool {
+	if c.Size != o.Size {
+		return false
+	}
+	if (c.Stickers == nil) != (o.Stickers == nil) || !slices.Equal(c.Stickers, o.Stickers) {
+		return false
+	}
+	for f := range 6 {
+		if string(c.Faces[f]) != string(o.Faces[f]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Key returns the stickers, followed by the tracked stickers if any, as a
+// string usable as a map key for exact state lookups.
+func (c *Cube) Key() string {
+	b := make([]byte, 0, 6*c.Size*c.Size+4*len(c.Stickers))
+	for f := range 6 {
+		b = append(b, c.Faces[f]...)
+	}
+	for _, v := range c.Stickers {
+		b = binary.LittleEndian.AppendUint32(b, uint32(v))
+	}
+	return string(b)
+}
+
+// FNV-1a parameters for Hash.
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// Hash returns a 64-bit FNV-1a hash of the size and stickers, and of the
+// tracked stickers if any, so equal keys give equal hashes.
+func (c *Cube) Hash() uint64 {
+	h := uint64(fnvOffset64)
+	h = (h ^ uint64(c.Size)) * fnvPrime64
+	for f := range 6 {
+		for _, v := range c.Faces[f] {
+			h = (h ^ uint64(v)) * fnvPrime64
+		}
+	}
+	for _, v := range c.Stickers {
+		for shift := 0; shift < 32; shift += 8 {
+			h = (h ^ uint64(byte(v>>shift))) * fnvPrime64
+		}
+	}
+	return h
+}
+
+// Hash128 returns a 128-bit FNV-1a hash of the size and stickers, and of
+// the tracked stickers if any, for tables large enough that 64-bit
+// collisions matter.
+func (c *Cube) Hash128() [2]uint64 {
+	h := fnv.New128a()
+	h.Write([]byte{byte(c.Size)})
+	for f := range 6 {
+		h.Write(c.Faces[f])
+	}
+	for _, v := range c.Stickers {
+		h.Write(binary.LittleEndian.AppendUint32(nil, uint32(v)))
+	}
+	var sum [16]byte
+	h.Sum(sum[:0])
+	var out [2]uint64
+	for i := range 8 {
+		out[0] = out[0]<<8 | uint64(sum[i])
+		out[1] = out[1]<<8 | uint64(sum[8+i])
+	}
+	return out
+}
+
+// CanonicalKey returns a key shared by every whole-cube rotation of the
+// state: the smallest Key among the 24 rotated copies. With relabel set,
+// colors are also renamed in order of first appearance before comparing,
+// so states that differ only by a color permutation share a key too; the
+// tracked stickers, if any, are not colors and are kept as they are.
+func (c *Cube) CanonicalKey(relabel bool) string {
+	best := ""
+	colors := 6 * c.Size * c.Size
+	for i, rot := range CubeRotations {
+		r := c.Copy()
+		rot.Apply(r)
+		key := r.Key()
+		if relabel {
+			key = relabelKey(key[:colors]) + key[colors:]
+		}
+		if i == 0 || key < best {
+			best = key
+		}
+	}
+	return best
+}
+
+// relabelKey renames the colors of a key in order of first appearance.
+func relabelKey(key string) string {
+	var names [256]byte
+	var next byte = 1
+	b := []byte(key)
+	for i, v := range b {
+		if names[v] == 0 {
+			names[v] = next
+			next++
+		}
+		b[i] = names[v] - 1
+	}
+	return string(b)
+}
@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestEqualAndHash(t *testing.T) {
+	a, b := NewCube(3), NewCube(3)
+	a.Moves("R U R' U'")
+	b.Moves("[R, U]")
+	if !a.Equal(b) || a.Hash() != b.Hash() || a.Hash128() != b.Hash128() || a.Key() != b.Key() {
+		t.Error("equal states should be Equal with equal hashes and keys")
+	}
+
+	b.Moves("U")
+	if a.Equal(b) || a.Hash() == b.Hash() || a.Hash128() == b.Hash128() || a.Key() == b.Key() {
+		t.Error("different states should differ in Equal, hashes and keys")
+	}
+	if NewCube(2).Equal(NewCube(3)) {
+		t.Error("cubes of different sizes should not be Equal")
+	}
+
+	// tracked cubes whose stickers look the same but whose centers differ
+	a, b = NewCube(3), NewCube(3)
+	a.TrackOrientation()
+	b.TrackOrientation()
+	b.Moves("(R U)105")
+	if a.Equal(b) || a.Hash() == b.Hash() || a.Hash128() == b.Hash128() || a.Key() == b.Key() {
+		t.Error("tracked stickers should count in Equal, hashes and keys")
+	}
+	if a.CanonicalKey(true) == b.CanonicalKey(true) {
+		t.Error("relabelling colors should keep the tracked stickers apart")
+	}
+	// a tracked and an untracked cube differ in their keys, so in Equal too
+	if a.Equal(NewCube(3)) || a.Key() == NewCube(3).Key() {
+		t.Error("a tracked cube should not equal an untracked one")
+	}
+	c := b.Copy()
+	c.Moves("y")
+	if b.CanonicalKey(true) != c.CanonicalKey(true) {
+		t.Error("a rotated tracked cube should share its canonical key")
+	}
+}
+
+func TestCanonicalKey(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, rot := range CubeRotations {
+		c := NewCube(3)
+		rot.Apply(c)
+		seen[c.Key()] = true
+	}
+	if len(seen) != 24 {
+		t.Fatalf("CubeRotations gives %d distinct orientations, want 24", len(seen))
+	}
+
+	rng := rand.New(rand.NewSource(9))
+	for n := 2; n <= 4; n++ {
+		for range 10 {
+			c := NewCube(n)
+			randomAlg(rng, 20).Apply(c)
+			key := c.CanonicalKey(false)
+			for _, rot := range CubeRotations {
+				r := c.Copy()
+				rot.Apply(r)
+				if r.CanonicalKey(false) != key {
+					t.Fatalf("%dx%d: CanonicalKey changes under %s", n, n, rot)
+				}
+			}
+		}
+	}
+
+	// R and B differ by a rotation only once the colors are renamed
+	r, b := NewCube(3), NewCube(3)
+	r.Moves("R")
+	b.Moves("B")
+	if r.CanonicalKey(false) == b.CanonicalKey(false) {
+		t.Error("R and B should have different keys without relabelling")
+	}
+	if r.CanonicalKey(true) != b.CanonicalKey(true) {
+		t.Error("R and B should share a key with relabelling")
+	}
+	u := NewCube(3)
+	u.Moves("R2")
+	if r.CanonicalKey(true) == u.CanonicalKey(true) {
+		t.Error("R and R2 should have different keys")
+	}
+}
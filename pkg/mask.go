@@ -0,0 +1,143 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// anyColor is the mask of a sticker that may show any color.
+const anyColor = 1<<6 - 1
+
+// Mask is a partial goal: for every sticker of an n×n cube, the set of
+// colors it may show, as a bitmask with bit f set for the color of face f.
+// A sticker is ignored when every color is allowed, matched exactly when one
+// is, and matched against a color class otherwise, e.g. {U,D} for the
+// stickers whose orientation matters in OLL.
+type Mask struct {
+	Size  int
+	Faces [6][]uint8
+}
+
+// NewMask returns a mask for an n×n cube that ignores every sticker.
+func NewMask(n int) *Mask {
+	m := &Mask{Size: n}
+	for f := range m.Faces {
+		m.Faces[f] = make([]uint8, n*n)
+		for i := range m.Faces[f] {
+			m.Faces[f][i] = anyColor
+		}
+	}
+	return m
+}
+
+// MaskOf returns a mask matching exactly the stickers of c.
+func MaskOf(c *Cube) *Mask {
+	m := NewMask(c.Size)
+	for f := range m.Faces {
+		for i, v := range c.Faces[f] {
+			m.Faces[f][i] = 1 << v
+		}
+	}
+	return m
+}
+
+// Matches reports whether every sticker of c shows an allowed color. Its
+// method value m.Matches can be used as a search CheckFunc.
+func (m *Mask) Matches(c *Cube) bool {
+	if c.Size != m.Size {
+		return false
+	}
+	for f := range m.Faces {
+		allowed := m.Faces[f]
+		for i, v := range c.Faces[f] {
+			if allowed[i]&(1<<v) == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String formats the mask as ParseMask reads it.
+func (m *Mask) String() string {
+	var b strings.Builder
+	for f := range m.Faces {
+		for _, bits := range m.Faces[f] {
+			switch {
+			case bits == anyColor:
+				b.WriteByte('.')
+			case bits&(bits-1) == 0 && bits != 0:
+				b.WriteByte(faceLetters[faceOfBit(bits)])
+			default:
+				b.WriteByte('{')
+				for col := range 6 {
+					if bits&(1<<col) != 0 {
+						b.WriteByte(faceLetters[col])
+					}
+				}
+				b.WriteByte('}')
+			}
+		}
+	}
+	return b.String()
+}
+
+// faceOfBit returns the face whose bit is the single bit set in bits.
+func faceOfBit(bits uint8) int {
+	f := 0
+	for bits > 1 {
+		bits >>= 1
+		f++
+	}
+	return f
+}
+
+// ParseMask reads a mask in facelet order (see Facelets), one entry per
+// sticker: a face letter matches that color exactly, '.' ignores the
+// sticker and a braced list of letters such as {UD} allows any of them.
+// The size is taken from the number of entries; whitespace is ignored.
+func ParseMask(s string) (*Mask, error) {
+	var entries []uint8
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == '.':
+			entries = append(entries, anyColor)
+		case c == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("mask sticker %d: unclosed {", len(entries)+1)
+			}
+			var bits uint8
+			for _, l := range []byte(s[i+1 : i+end]) {
+				col := strings.IndexByte(faceLetters, l)
+				if col < 0 {
+					return nil, fmt.Errorf("mask sticker %d: invalid color %q, want one of %s", len(entries)+1, l, faceLetters)
+				}
+				bits |= 1 << col
+			}
+			if bits == 0 {
+				return nil, fmt.Errorf("mask sticker %d: empty color class", len(entries)+1)
+			}
+			entries = append(entries, bits)
+			i += end
+		default:
+			col := strings.IndexByte(faceLetters, c)
+			if col < 0 {
+				return nil, fmt.Errorf("mask sticker %d: invalid color %q, want one of %s, . or {…}", len(entries)+1, c, faceLetters)
+			}
+			entries = append(entries, 1<<col)
+		}
+	}
+
+	n := int(math.Round(math.Sqrt(float64(len(entries)) / 6)))
+	if n < 2 || 6*n*n != len(entries) {
+		return nil, fmt.Errorf("mask has %d stickers, want 6n² for some n ≥ 2", len(entries))
+	}
+	m := &Mask{Size: n}
+	for f := range m.Faces {
+		m.Faces[f] = entries[f*n*n : (f+1)*n*n]
+	}
+	return m, nil
+}
@@ -0,0 +1,58 @@
+package pkg
+
+import "testing"
+
+func TestMask(t *testing.T) {
+	// OLL goal: the first two layers solved and the U face oriented
+	oll, err := ParseMask(`
+		UUUUUUUUU ...RRRRRR ...FFFFFF
+		DDDDDDDDD ...LLLLLL ...BBBBBB`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a color class: only the U/D stickers are checked
+	ud, err := ParseMask("{UD}{UD}{UD}{UD}{UD}{UD}{UD}{UD}{UD}" + "........." + "........." +
+		"{DU}{DU}{DU}{DU}{DU}{DU}{DU}{DU}{DU}" + "........." + ".........")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		alg     string
+		oll, ud bool
+	}{
+		{"", true, true},
+		{"R U R' U' R' F R2 U' R' U' R U R' F'", true, true},
+		{"R U R' U R U2 R'", false, false},
+		{"R2 U R2", false, true},
+		{"R", false, false},
+	} {
+		c := NewCube(3)
+		c.Moves(tt.alg)
+		if got := oll.Matches(c); got != tt.oll {
+			t.Errorf("%q: OLL mask = %v, want %v", tt.alg, got, tt.oll)
+		}
+		if got := ud.Matches(c); got != tt.ud {
+			t.Errorf("%q: U/D mask = %v, want %v", tt.alg, got, tt.ud)
+		}
+	}
+
+	if ud.String()[:4] != "{UD}" {
+		t.Errorf("String() = %q, want a {UD} class first", ud)
+	}
+	for _, m := range []*Mask{oll, ud, NewMask(2), MaskOf(NewCube(4))} {
+		back, err := ParseMask(m.String())
+		if err != nil || back.String() != m.String() {
+			t.Errorf("ParseMask(%q) = %v, %v; want a round trip", m, back, err)
+		}
+	}
+	if !NewMask(2).Matches(NewCube(2)) || NewMask(2).Matches(NewCube(3)) {
+		t.Error("an empty mask should match any cube of its size only")
+	}
+
+	for _, bad := range []string{"", "UUUU", "{UD" + "........................", "X......................", "{}......................."} {
+		if _, err := ParseMask(bad); err == nil {
+			t.Errorf("ParseMask(%q): expected error", bad)
+		}
+	}
+}
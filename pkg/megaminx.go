@@ -0,0 +1,486 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Megaminx faces. The upper faces ring U clockwise from F, and each lower
+// face is opposite the upper face six places before it, e.g. B opposite F.
+const (
+	MegaU = iota
+	MegaF
+	MegaR
+	MegaBR
+	MegaBL
+	MegaL
+	MegaD
+	MegaB
+	MegaDBL
+	MegaDL
+	MegaDR
+	MegaDBR
+)
+
+// megaFaces names the faces in order.
+var megaFaces = [12]string{"U", "F", "R", "BR", "BL", "L", "D", "B", "DBL", "DL", "DR", "DBR"}
+
+// megaLetters are the color letters of the faces for Display and masks;
+// the face opposite each upper face uses the lower-case letter.
+const megaLetters = "WGRBYPwgrbyp"
+
+// megaTurns are the suffixes of a face turn by one or two fifths, in move
+// index order.
+var megaTurns = [4]string{"", "2", "'", "2'"}
+
+// Megaminx is a sticker model of the Megaminx: twelve faces of eleven
+// stickers, each byte holding the face index of its color. A face stores
+// its center, then its corners C0-C4 clockwise and the edges E0-E4, where
+// Ei lies between Ci and the next corner. C0 is the top corner of faces
+// printed point up and the top left corner of faces printed point down,
+// see DisplayColorANSI.
+//
+// A move index (see Puzzle) is face*4 + turn for the face turns, where turn
+// indexes megaTurns, followed by the Pochmann moves R++ R-- D++ D-- used in
+// WCA scrambles: R++ turns everything but the L layer two fifths clockwise
+// about the DBR axis, D++ everything but U about the D axis.
+type Megaminx struct {
+	Stickers [132]byte
+}
+
+var _ Puzzle[*Megaminx] = (*Megaminx)(nil)
+
+// megaTables holds the sticker permutation of each of the 52 moves: the
+// sticker at i moves to megaTables[m][i]. megaPointUp records which faces
+// are printed with a corner at the top.
+var megaTables, megaPointUp = buildMegaTables()
+
+// NewMegaminx returns a solved Megaminx.
+func NewMegaminx() *Megaminx {
+	p := &Megaminx{}
+	for i := range p.Stickers {
+		p.Stickers[i] = byte(i / 11)
+	}
+	return p
+}
+
+// Copy returns a copy of the state.
+func (p *Megaminx) Copy() *Megaminx {
+	c := *p
+	return &c
+}
+
+// Face returns the stickers of face f.
+func (p *Megaminx) Face(f int) []byte { return p.Stickers[11*f : 11*f+11] }
+
+// Apply performs the move with the given index.
+func (p *Megaminx) Apply(move int) {
+	old := p.Stickers
+	for i, to := range megaTables[move] {
+		p.Stickers[to] = old[i]
+	}
+}
+
+// InverseMove returns the index of the opposite turn.
+func (p *Megaminx) InverseMove(move int) int {
+	if move >= 48 {
+		return move ^ 1
+	}
+	return move ^ 2
+}
+
+// Key returns the stickers as a string.
+func (p *Megaminx) Key() string { return string(p.Stickers[:]) }
+
+// IsSolved reports whether every face shows a single color. Pochmann moves
+// turn the centers, so any orientation of the whole puzzle counts.
+func (p *Megaminx) IsSolved() bool {
+	for i, v := range p.Stickers {
+		if v != p.Stickers[i/11*11] {
+			return false
+		}
+	}
+	return true
+}
+
+// MoveNotation formats a move index, e.g. R, U2' or D++.
+func (p *Megaminx) MoveNotation(move int) string {
+	if move >= 48 {
+		return []string{"R++", "R--", "D++", "D--"}[move-48]
+	}
+	return megaFaces[move/4] + megaTurns[move%4]
+}
+
+// MoveGroup groups the turns of each face, and each pair of Pochmann
+// moves, together.
+func (p *Megaminx) MoveGroup(move int) int {
+	if move >= 48 {
+		return 12 + (move-48)/2
+	}
+	return move / 4
+}
+
+// ParseMegaMove parses a Megaminx move: a face name followed by nothing,
+// 2, ' or 2', or one of R++ R-- D++ D--.
+func ParseMegaMove(s string) (int, error) {
+	switch s {
+	case "R++", "R--", "D++", "D--":
+		move := 48
+		if s[0] == 'D' {
+			move += 2
+		}
+		if s[1] == '-' {
+			move++
+		}
+		return move, nil
+	}
+	name, turn := s, 0
+	for t := len(megaTurns) - 1; t > 0; t-- {
+		if n, ok := strings.CutSuffix(s, megaTurns[t]); ok {
+			name, turn = n, t
+			break
+		}
+	}
+	for f, face := range megaFaces {
+		if name == face {
+			return f*4 + turn, nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnknownMove, s)
+}
+
+// ParseMoves parses space separated Megaminx moves.
+func (p *Megaminx) ParseMoves(s string) ([]int, error) {
+	var moves []int
+	for i, tok := range strings.Fields(s) {
+		m, err := ParseMegaMove(tok)
+		if err != nil {
+			return nil, &ParseError{Index: i, Token: tok, Err: err}
+		}
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
+
+// Moves parses and applies space separated moves.
+func (p *Megaminx) Moves(s string) error {
+	moves, err := p.ParseMoves(s)
+	if err != nil {
+		return err
+	}
+	for _, m := range moves {
+		p.Apply(m)
+	}
+	return nil
+}
+
+// megaNormals returns the outward unit normal of every face. The upper
+// faces sit at an elevation of atan(1/2), F facing +z and the ring turning
+// towards +x; the lower faces point the other way.
+func megaNormals() [12]vec3 {
+	var n [12]vec3
+	n[MegaU] = vec3{0, 1, 0}
+	s, c := 1/math.Sqrt(5), 2/math.Sqrt(5)
+	for i := range 5 {
+		az := float64(i) * 2 * math.Pi / 5
+		n[MegaF+i] = vec3{c * math.Sin(az), s, c * math.Cos(az)}
+	}
+	for f := range 6 {
+		n[f+6] = n[f].scale(-1)
+	}
+	return n
+}
+
+// megaStickers returns the centre of every sticker of a Megaminx with
+// faces one unit from its centre, and which faces DisplayColorANSI prints
+// point up. Each face is seen from outside with U towards the top, except
+// U with B and D with F towards the top.
+func megaStickers() (pts [132]vec3, pointUp [12]bool) {
+	normals := megaNormals()
+	for f, n := range normals {
+		// a corner lies on f and two neighbours of f that meet each other
+		var adj []vec3
+		for _, o := range normals {
+			if d := n.dot(o); d > 0.4 && d < 0.5 {
+				adj = append(adj, o)
+			}
+		}
+		var verts []vec3
+		for i, a := range adj {
+			for _, b := range adj[i+1:] {
+				if d := a.dot(b); d > 0.4 && d < 0.5 {
+					// the point one unit along n, a and b
+					det := n.dot(a.cross(b))
+					verts = append(verts, a.cross(b).add(b.cross(n)).add(n.cross(a)).scale(1/det))
+				}
+			}
+		}
+
+		up := vec3{0, 1, 0}
+		switch f {
+		case MegaU:
+			up = vec3{0, 0, -1}
+		case MegaD:
+			up = vec3{0, 0, 1}
+		}
+		up = up.sub(n.scale(up.dot(n))).norm()
+		right := up.cross(n)
+		angle := func(v vec3) float64 {
+			d := v.sub(n)
+			// clockwise from a corner at the top or the top left corner
+			a := math.Atan2(d.dot(right), d.dot(up)) + math.Pi/5 + 1e-6
+			return math.Mod(a+2*math.Pi, 2*math.Pi)
+		}
+		sort.Slice(verts, func(i, j int) bool { return angle(verts[i]) < angle(verts[j]) })
+		pointUp[f] = math.Abs(angle(verts[0])-math.Pi/5) < 1e-3
+
+		pts[11*f] = n
+		for k, v := range verts {
+			mid := v.add(verts[(k+1)%5]).scale(0.5)
+			pts[11*f+1+k] = v.add(n.sub(v).scale(0.3))
+			pts[11*f+6+k] = mid.add(n.sub(mid).scale(0.3))
+		}
+	}
+	return pts, pointUp
+}
+
+// buildMegaTables computes the sticker permutation of every move by turning
+// the stickers beyond a cut plane about a face axis. The cut passes between
+// the stickers of a face layer and the rest of its neighbours' stickers.
+func buildMegaTables() ([52][132]int, [12]bool) {
+	pts, pointUp := megaStickers()
+	normals := megaNormals()
+	const cut = 0.75
+	turn := func(axis vec3, cut, angle float64) [132]int {
+		var table [132]int
+		for i, pt := range pts {
+			table[i] = i
+			if pt.dot(axis) <= cut {
+				continue
+			}
+			to := pt.rotate(axis, angle)
+			for j, q := range pts {
+				if d := to.sub(q); d.dot(d) < 1e-9 {
+					table[i] = j
+				}
+			}
+		}
+		return table
+	}
+
+	var tables [52][132]int
+	fifth := 2 * math.Pi / 5
+	for f, n := range normals {
+		for t, angle := range []float64{-fifth, -2 * fifth, fifth, 2 * fifth} {
+			tables[f*4+t] = turn(n, cut, angle)
+		}
+	}
+	// everything but the opposite layer
+	for i, f := range []int{MegaDBR, MegaD} {
+		tables[48+2*i] = turn(normals[f], -cut, -2*fifth)
+		tables[49+2*i] = turn(normals[f], -cut, 2*fifth)
+	}
+	return tables, pointUp
+}
+
+// MegaminxMask is a partial goal for the Megaminx: for every sticker, the
+// set of colors it may show, as a bitmask with bit f set for the color of
+// face f.
+type MegaminxMask [132]uint16
+
+// megaAnyColor is the mask of a sticker that may show any color.
+const megaAnyColor = 1<<12 - 1
+
+// MegaminxMaskOf returns a mask matching exactly the stickers of p.
+func MegaminxMaskOf(p *Megaminx) *MegaminxMask {
+	m := &MegaminxMask{}
+	for i, v := range p.Stickers {
+		m[i] = 1 << v
+	}
+	return m
+}
+
+// Matches reports whether every sticker of p shows an allowed color. Its
+// method value can be used as a search check.
+func (m *MegaminxMask) Matches(p *Megaminx) bool {
+	for i, v := range p.Stickers {
+		if m[i]&(1<<v) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the mask as ParseMegaminxMask reads it, one face per
+// group.
+func (m *MegaminxMask) String() string {
+	var b strings.Builder
+	for i, bits := range m {
+		if i > 0 && i%11 == 0 {
+			b.WriteByte(' ')
+		}
+		switch {
+		case bits == megaAnyColor:
+			b.WriteByte('.')
+		case bits&(bits-1) == 0 && bits != 0:
+			for col := range 12 {
+				if bits == 1<<col {
+					b.WriteByte(megaLetters[col])
+				}
+			}
+		default:
+			b.WriteByte('{')
+			for col := range 12 {
+				if bits&(1<<col) != 0 {
+					b.WriteByte(megaLetters[col])
+				}
+			}
+			b.WriteByte('}')
+		}
+	}
+	return b.String()
+}
+
+// ParseMegaminxMask reads a mask with one entry per sticker in the order of
+// Megaminx.Stickers: a color letter (see Display) matches that color
+// exactly, '.' ignores the sticker and a braced list of letters such as
+// {WG} allows any of them. Whitespace is ignored, so faces can be grouped.
+func ParseMegaminxMask(s string) (*MegaminxMask, error) {
+	m := &MegaminxMask{}
+	n := 0
+	add := func(bits uint16) error {
+		if n == len(m) {
+			return fmt.Errorf("mask has more than %d stickers", len(m))
+		}
+		m[n] = bits
+		n++
+		return nil
+	}
+	for i := 0; i < len(s); i++ {
+		var bits uint16
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			continue
+		case c == '.':
+			bits = megaAnyColor
+		case c == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("mask sticker %d: unclosed {", n+1)
+			}
+			for _, l := range []byte(s[i+1 : i+end]) {
+				col := strings.IndexByte(megaLetters, l)
+				if col < 0 {
+					return nil, fmt.Errorf("mask sticker %d: invalid color %q, want one of %s", n+1, l, megaLetters)
+				}
+				bits |= 1 << col
+			}
+			if bits == 0 {
+				return nil, fmt.Errorf("mask sticker %d: empty color class", n+1)
+			}
+			i += end
+		default:
+			col := strings.IndexByte(megaLetters, c)
+			if col < 0 {
+				return nil, fmt.Errorf("mask sticker %d: invalid color %q, want one of %s, . or {…}", n+1, c, megaLetters)
+			}
+			bits = 1 << col
+		}
+		if err := add(bits); err != nil {
+			return nil, err
+		}
+	}
+	if n != len(m) {
+		return nil, fmt.Errorf("mask has %d stickers, want %d", n, len(m))
+	}
+	return m, nil
+}
+
+// ParseMask parses a mask with ParseMegaminxMask and returns its Matches
+// method as a search goal.
+func (p *Megaminx) ParseMask(s string) (func(*Megaminx) bool, error) {
+	m, err := ParseMegaminxMask(s)
+	if err != nil {
+		return nil, err
+	}
+	return m.Matches, nil
+}
+
+// megaColors are the ANSI backgrounds of the faces: white, green, red,
+// blue, yellow and purple above, then grey, lime, orange, light blue,
+// beige and pink opposite them.
+var megaColors = [12]string{
+	"\x1b[48;5;231m", "\x1b[48;5;28m", "\x1b[48;5;196m", "\x1b[48;5;21m", "\x1b[48;5;226m", "\x1b[48;5;91m",
+	"\x1b[48;5;246m", "\x1b[48;5;118m", "\x1b[48;5;208m", "\x1b[48;5;45m", "\x1b[48;5;223m", "\x1b[48;5;213m",
+}
+
+// megaLayouts place the stickers of a face printed point down and point
+// up in four rows of five cells; -1 is a blank cell.
+var megaLayouts = map[bool][4][5]int{
+	false: {
+		{-1, 1, 6, 2, -1},
+		{5, 10, 0, 7, 3},
+		{-1, 9, -1, 8, -1},
+		{-1, -1, 4, -1, -1},
+	},
+	true: {
+		{-1, -1, 1, -1, -1},
+		{5, 10, 0, 6, 2},
+		{-1, 9, -1, 7, -1},
+		{-1, 4, 8, 3, -1},
+	},
+}
+
+// megaNet places the faces of the net in rows of two flowers: U ringed by
+// its neighbours as seen from above, then D as seen from below with the
+// front at the top. Offsets count cells.
+var megaNet = [][]struct{ face, offset int }{
+	{{MegaBL, 3}, {MegaBR, 9}},
+	{{MegaL, 0}, {MegaU, 6}, {MegaR, 12}},
+	{{MegaF, 6}},
+	{{MegaDL, 3}, {MegaDR, 9}},
+	{{MegaDBL, 0}, {MegaD, 6}, {MegaDBR, 12}},
+	{{MegaB, 6}},
+}
+
+// Display prints the net in ASCII with color letters.
+func (p *Megaminx) Display() {
+	p.display("  ", func(v byte) string { return string(megaLetters[v]) + " " })
+}
+
+// DisplayColorANSI prints the net with ANSI-colored stickers. Every face
+// is drawn as seen from outside with U towards the top, so the side faces
+// of the upper half point down and those of the lower half point up; U is
+// drawn with B and D with F towards the top.
+func (p *Megaminx) DisplayColorANSI() {
+	p.display(sticker, func(v byte) string { return megaColors[v] + sticker + reset })
+}
+
+// display prints the net, painting stickers with paint and filling blank
+// cells with blank.
+func (p *Megaminx) display(blank string, paint func(byte) string) {
+	for i, row := range megaNet {
+		if i == 3 {
+			fmt.Println()
+		}
+		for r := range 4 {
+			var b strings.Builder
+			col := 0
+			for _, slot := range row {
+				b.WriteString(strings.Repeat(blank, slot.offset-col))
+				face := p.Face(slot.face)
+				for _, s := range megaLayouts[megaPointUp[slot.face]][r] {
+					if s < 0 {
+						b.WriteString(blank)
+					} else {
+						b.WriteString(paint(face[s]))
+					}
+				}
+				col = slot.offset + 5
+			}
+			fmt.Println(strings.TrimRight(b.String(), " "))
+		}
+	}
+}
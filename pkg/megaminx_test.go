@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMegaminxMoves(t *testing.T) {
+	for m := range 52 {
+		p := NewMegaminx()
+		name := p.MoveNotation(m)
+		if got, err := ParseMegaMove(name); err != nil || got != m {
+			t.Errorf("ParseMegaMove(%q) = %d, %v; want %d", name, got, err, m)
+		}
+
+		// a face turn changes the 15 stickers its neighbours lend the layer
+		p.Apply(m)
+		moved := 0
+		for i, v := range p.Stickers {
+			if v != byte(i/11) {
+				moved++
+			}
+		}
+		if m < 48 && moved != 15 {
+			t.Errorf("%s moves %d stickers, want 15", name, moved)
+		}
+		for range 4 {
+			p.Apply(m)
+		}
+		if !p.IsSolved() {
+			t.Errorf("five %s turns should solve", name)
+		}
+		p.Apply(m)
+		p.Apply(p.InverseMove(m))
+		if !p.IsSolved() {
+			t.Errorf("%s %s should solve", name, p.MoveNotation(p.InverseMove(m)))
+		}
+	}
+}
+
+func TestMegaminxPochmann(t *testing.T) {
+	// R++ leaves the L layer alone and D++ the U layer
+	for _, tc := range []struct {
+		move  string
+		fixed int
+	}{{"R++", MegaL}, {"D--", MegaU}} {
+		p := NewMegaminx()
+		p.Moves(tc.move)
+		for i, v := range p.Face(tc.fixed) {
+			if v != byte(tc.fixed) {
+				t.Errorf("%s changes sticker %d of %s", tc.move, i, megaFaces[tc.fixed])
+			}
+		}
+	}
+
+	scramble := "R++ D-- R-- D++ R++ D++ U R-- D-- U'"
+	p := NewMegaminx()
+	if err := p.Moves(scramble); err != nil {
+		t.Fatal(err)
+	}
+	if p.IsSolved() {
+		t.Errorf("%s should scramble", scramble)
+	}
+	p.Moves("U D++ R++ U' D-- R-- D-- R++ D++ R--")
+	if !p.IsSolved() {
+		t.Errorf("%s and its inverse should solve", scramble)
+	}
+
+	var pe *ParseError
+	if err := p.Moves("R U++"); !errors.As(err, &pe) || pe.Index != 1 || !errors.Is(err, ErrUnknownMove) {
+		t.Errorf("R U++: got %v, want ErrUnknownMove at 1", err)
+	}
+}
+
+func TestMegaminxMask(t *testing.T) {
+	// F2L solved and the U edges oriented
+	const eo = "W.....WWWWW G..GGG.GGGG R..RRR.RRRR B..BBB.BBBB Y..YYY.YYYY P..PPP.PPPP " +
+		"wwwwwwwwwww ggggggggggg rrrrrrrrrrr bbbbbbbbbbb yyyyyyyyyyy ppppppppppp"
+	m, err := ParseMegaminxMask(eo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.String() != eo {
+		t.Errorf("String() = %q", m)
+	}
+	for s, want := range map[string]bool{
+		"":                              true,
+		"R U R' U R U2' R'":             true, // Sune twists corners only
+		"F R U R' U' F'":                false,
+		"F R U R' U' F' F U R U' R' F'": true,
+		"R":                             false,
+	} {
+		p := NewMegaminx()
+		p.Moves(s)
+		if m.Matches(p) != want {
+			t.Errorf("mask matches %q = %v, want %v", s, !want, want)
+		}
+	}
+
+	for _, bad := range []string{"W", eo + "W", "X" + eo[1:], "{" + eo[1:], "{}" + eo[1:]} {
+		if _, err := ParseMegaminxMask(bad); err == nil {
+			t.Errorf("ParseMegaminxMask(%.12q...): expected error", bad)
+		}
+	}
+}
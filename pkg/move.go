@@ -0,0 +1,339 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MoveKind classifies a move by how many layers it turns.
+type MoveKind uint8
+
+const (
+	MoveFace      MoveKind = iota // single layer turn, e.g. R, or the inner slice 3R
+	MoveWide                      // block of layers, e.g. Rw, r, 3Rw, 2-3Rw
+	MoveSlice                     // middle slice, e.g. M, E, S
+	MoveRotation                  // whole-cube rotation, e.g. x, y, z
+	MoveWideSlice                 // every inner layer, e.g. m, e, s
+)
+
+// Move is a single parsed turn in SiGN notation such as "R", "U2'", "3Rw",
+// "2-3Rw", "4r" or "x'".
+//
+// Face is the face the turn follows (Uface..Bface). Slices follow the face
+// they share a direction with (M→L, E→D, S→F) and rotations follow their
+// axis face (x→R, y→U, z→F). Amount is the number of clockwise quarter
+// turns: 1, 2, -1 (prime) or -2 (2').
+//
+// Face and wide moves turn Layers layers after skipping Offset outer
+// layers, so 3R has Offset 2 and Layers 1 and 2-3Rw has Offset 1 and
+// Layers 2. Slices and rotations depend on the cube size and leave both 0:
+// M turns the middle layer of an odd cube and the two central layers of an
+// even one, m turns every layer but the outer two.
+type Move struct {
+	Face   int
+	Layers int // number of layers turned; 0 for slices and rotations
+	Amount int
+	Kind   MoveKind
+	Offset int // outer layers left in place before the turning ones
+}
+
+// faceLetters, rotationLetters and sliceLetters map a Move.Face to its
+// notation letter for each kind of move.
+const faceLetters = "URFDLB"
+
+var (
+	rotationLetters = [6]byte{Uface: 'y', Rface: 'x', Fface: 'z'}
+	sliceLetters    = [6]byte{Dface: 'E', Lface: 'M', Fface: 'S'}
+)
+
+// lower converts an ASCII letter to lower case.
+func lower(c byte) byte { return c | 0x20 }
+
+// letterFace returns the face whose entry in letters is c, or -1.
+func letterFace(letters [6]byte, c byte) int {
+	for f, l := range letters {
+		if l != 0 && l == c {
+			return f
+		}
+	}
+	return -1
+}
+
+// Errors returned (wrapped in a *ParseError by ParseAlg) for malformed moves.
+var (
+	ErrUnknownMove = errors.New("unknown move")
+	ErrBadSuffix   = errors.New("bad suffix")
+	ErrBadWidth    = errors.New("bad width")
+)
+
+// ParseError reports a token that could not be parsed or a move that is not
+// valid on a cube, with its zero-based position in the sequence.
+type ParseError struct {
+	Index int    // index of the offending token, or -1 for a move of a parsed alg
+	Move  int    // index of the offending move in its alg when Index is -1
+	Token string // the offending token or move
+	Err   error  // ErrUnknownMove, ErrBadSuffix, ErrBadWidth or ErrSyntax, possibly wrapped
+}
+
+// moveError reports the move at index i of a parsed alg, which may have
+// come from any number of tokens.
+func moveError(i int, m fmt.Stringer, err error) *ParseError {
+	return &ParseError{Index: -1, Move: i, Token: m.String(), Err: err}
+}
+
+func (e *ParseError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("move %d %q: %v", e.Move+1, e.Token, e.Err)
+	}
+	return fmt.Sprintf("token %d %q: %v", e.Index+1, e.Token, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// moveSuffixes maps every accepted suffix to its quarter-turn amount.
+var moveSuffixes = map[string]int{"": 1, "'": -1, "2": 2, "2'": -2}
+
+// parseLayers reads a leading layer count "3" or range "2-3".
+func parseLayers(s string) (lo, hi int, rest string, ok, isRange bool) {
+	digits := func(s string) (int, string) {
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == 0 {
+			return -1, s
+		}
+		n, _ := strconv.Atoi(s[:i])
+		return n, s[i:]
+	}
+
+	hi, rest = digits(s)
+	if hi < 0 {
+		return 0, 0, s, false, false
+	}
+	lo = hi
+	if strings.HasPrefix(rest, "-") {
+		if hi, rest = digits(rest[1:]); hi < 0 {
+			return 0, 0, s, false, false
+		}
+		isRange = true
+	}
+	return lo, hi, rest, true, isRange
+}
+
+// ParseMove parses a single move token in SiGN notation such as "R2'",
+// "u", "3Rw", "3r", "2R", "2-3Rw", "M", "m" or "x".
+func ParseMove(notation string) (Move, error) {
+	m := Move{Layers: 1}
+
+	// leading layer count or range
+	lo, hi, s, hasWidth, isRange := parseLayers(notation)
+	if len(s) == 0 {
+		return Move{}, ErrUnknownMove
+	}
+
+	// move letter, with an optional w for wide moves
+	letter, s := s[0], s[1:]
+	wide := false
+	if len(s) > 0 && s[0] == 'w' {
+		wide = true
+		s = s[1:]
+	} else if letter >= 'a' && letter <= 'z' && strings.IndexByte(faceLetters, letter-'a'+'A') >= 0 {
+		// r style wide move
+		wide = true
+		letter = letter - 'a' + 'A'
+	}
+
+	// whatever is left must be a turn amount
+	amount, ok := moveSuffixes[s]
+	if !ok {
+		return Move{}, fmt.Errorf("%w %q", ErrBadSuffix, s)
+	}
+	m.Amount = amount
+
+	switch {
+	case strings.IndexByte(faceLetters, letter) >= 0:
+		m.Face = strings.IndexByte(faceLetters, letter)
+		switch {
+		case isRange && (!wide || lo < 1 || lo >= hi):
+			return Move{}, fmt.Errorf("%w %d-%d", ErrBadWidth, lo, hi)
+		case isRange:
+			// 2-3Rw turns layers 2 to 3
+			m.Kind, m.Offset, m.Layers = MoveWide, lo-1, hi-lo+1
+		case wide && !hasWidth:
+			m.Kind, m.Layers = MoveWide, 2
+		case wide:
+			// 3Rw turns the outer 3 layers
+			if hi < 2 {
+				return Move{}, fmt.Errorf("%w %d", ErrBadWidth, hi)
+			}
+			m.Kind, m.Layers = MoveWide, hi
+		case hasWidth:
+			// 3R turns the third layer only
+			if hi < 1 {
+				return Move{}, fmt.Errorf("%w %d", ErrBadWidth, hi)
+			}
+			m.Kind, m.Offset = MoveFace, hi-1
+		default:
+			m.Kind = MoveFace
+		}
+
+	case !wide && !hasWidth && letterFace(rotationLetters, letter) >= 0:
+		m.Face = letterFace(rotationLetters, letter)
+		m.Kind = MoveRotation
+		m.Layers = 0
+
+	case !wide && !hasWidth && letterFace(sliceLetters, letter) >= 0:
+		m.Face = letterFace(sliceLetters, letter)
+		m.Kind = MoveSlice
+		m.Layers = 0
+
+	case !wide && !hasWidth && letter >= 'a' && letterFace(sliceLetters, letter-'a'+'A') >= 0:
+		m.Face = letterFace(sliceLetters, letter-'a'+'A')
+		m.Kind = MoveWideSlice
+		m.Layers = 0
+
+	default:
+		return Move{}, ErrUnknownMove
+	}
+
+	return m, nil
+}
+
+// layerRange returns the first and last layer, counted from 1 at m.Face,
+// that m turns on an n×n cube.
+func (m Move) layerRange(n int) (lo, hi int) {
+	switch m.Kind {
+	case MoveRotation:
+		return 1, n
+	case MoveSlice:
+		if n%2 == 1 {
+			return (n + 1) / 2, (n + 1) / 2
+		}
+		return n / 2, n/2 + 1
+	case MoveWideSlice:
+		return 2, n - 1
+	default:
+		return m.Offset + 1, m.Offset + m.Layers
+	}
+}
+
+// Validate reports whether m can be performed on an n×n cube.
+func (m Move) Validate(n int) error {
+	_, hi := m.layerRange(n)
+	switch {
+	case hi > n:
+		return fmt.Errorf("%w: layer %d on a %dx%d cube", ErrBadWidth, hi, n, n)
+	case (m.Kind == MoveSlice || m.Kind == MoveWideSlice) && n < 3:
+		return fmt.Errorf("%w: no slice on a %dx%d cube", ErrBadWidth, n, n)
+	case m.Layers > maxIndexLayers || m.Offset > maxIndexLayers:
+		return fmt.Errorf("%w: more than %d layers", ErrBadWidth, maxIndexLayers)
+	}
+	return nil
+}
+
+// String formats the move in SiGN notation.
+func (m Move) String() string {
+	var b strings.Builder
+
+	switch m.Kind {
+	case MoveFace:
+		if m.Offset > 0 {
+			b.WriteString(strconv.Itoa(m.Offset + 1))
+		}
+		b.WriteByte(faceLetters[m.Face])
+	case MoveWide:
+		if m.Offset > 0 {
+			fmt.Fprintf(&b, "%d-%d", m.Offset+1, m.Offset+m.Layers)
+		} else if m.Layers > 2 {
+			b.WriteString(strconv.Itoa(m.Layers))
+		}
+		b.WriteByte(faceLetters[m.Face])
+		b.WriteByte('w')
+	case MoveSlice:
+		b.WriteByte(sliceLetters[m.Face])
+	case MoveWideSlice:
+		b.WriteByte(lower(sliceLetters[m.Face]))
+	case MoveRotation:
+		b.WriteByte(rotationLetters[m.Face])
+	}
+
+	switch m.Amount {
+	case 2:
+		b.WriteByte('2')
+	case -1:
+		b.WriteByte('\'')
+	case -2:
+		b.WriteString("2'")
+	}
+
+	return b.String()
+}
+
+// Inverse returns the move that undoes m.
+func (m Move) Inverse() Move {
+	m.Amount = -m.Amount
+	return m
+}
+
+// IsRotation reports whether m is a whole-cube rotation.
+func (m Move) IsRotation() bool { return m.Kind == MoveRotation }
+
+// isSlice reports whether m is an M/E/S style move.
+func (m Move) isSlice() bool { return m.Kind == MoveSlice || m.Kind == MoveWideSlice }
+
+// sameFace reports whether two moves share a notation letter (e.g. R and
+// Rw), so that searching both in a row would be redundant.
+func (m Move) sameFace(o Move) bool {
+	return m.Face == o.Face &&
+		m.isSlice() == o.isSlice() &&
+		(m.Kind == MoveRotation) == (o.Kind == MoveRotation)
+}
+
+// Apply performs the move on c.
+func (m Move) Apply(c *Cube) {
+	lo, hi := m.layerRange(c.Size)
+	c.turnLayers(m.Face, lo, hi, m.Amount)
+}
+
+// Metric selects how moves are counted by Alg.Len.
+type Metric int
+
+const (
+	HTM Metric = iota // half-turn metric: slices count 2, rotations 0
+	QTM               // quarter-turn metric: half turns count 2, slices double
+	STM               // slice-turn metric: every turn counts 1, rotations 0
+	ETM               // execution-turn metric: every move counts 1
+)
+
+// Len returns the cost of m under the given metric.
+func (m Move) Len(metric Metric) int {
+	if m.Kind == MoveRotation {
+		if metric == ETM {
+			return 1
+		}
+		return 0
+	}
+
+	quarters := m.Amount
+	if quarters < 0 {
+		quarters = -quarters
+	}
+
+	switch metric {
+	case HTM:
+		if m.isSlice() {
+			return 2
+		}
+		return 1
+	case QTM:
+		if m.isSlice() {
+			return 2 * quarters
+		}
+		return quarters
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,147 @@
+package pkg
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseSiGN(t *testing.T) {
+	tests := []struct {
+		in, out string
+		want    Move
+	}{
+		{"R", "R", Move{Face: Rface, Layers: 1, Amount: 1, Kind: MoveFace}},
+		{"2R", "2R", Move{Face: Rface, Layers: 1, Amount: 1, Kind: MoveFace, Offset: 1}},
+		{"1R'", "R'", Move{Face: Rface, Layers: 1, Amount: -1, Kind: MoveFace}},
+		{"12U2", "12U2", Move{Face: Uface, Layers: 1, Amount: 2, Kind: MoveFace, Offset: 11}},
+		{"Rw", "Rw", Move{Face: Rface, Layers: 2, Amount: 1, Kind: MoveWide}},
+		{"r", "Rw", Move{Face: Rface, Layers: 2, Amount: 1, Kind: MoveWide}},
+		{"3r'", "3Rw'", Move{Face: Rface, Layers: 3, Amount: -1, Kind: MoveWide}},
+		{"10Rw", "10Rw", Move{Face: Rface, Layers: 10, Amount: 1, Kind: MoveWide}},
+		{"2-3Rw", "2-3Rw", Move{Face: Rface, Layers: 2, Amount: 1, Kind: MoveWide, Offset: 1}},
+		{"3-10f2", "3-10Fw2", Move{Face: Fface, Layers: 8, Amount: 2, Kind: MoveWide, Offset: 2}},
+		{"1-3Lw", "3Lw", Move{Face: Lface, Layers: 3, Amount: 1, Kind: MoveWide}},
+		{"M'", "M'", Move{Face: Lface, Amount: -1, Kind: MoveSlice}},
+		{"m", "m", Move{Face: Lface, Amount: 1, Kind: MoveWideSlice}},
+		{"e2", "e2", Move{Face: Dface, Amount: 2, Kind: MoveWideSlice}},
+		{"s'", "s'", Move{Face: Fface, Amount: -1, Kind: MoveWideSlice}},
+	}
+	for _, tt := range tests {
+		m, err := ParseMove(tt.in)
+		if err != nil {
+			t.Errorf("ParseMove(%q): %v", tt.in, err)
+			continue
+		}
+		if m != tt.want {
+			t.Errorf("ParseMove(%q) = %+v, want %+v", tt.in, m, tt.want)
+		}
+		if m.String() != tt.out {
+			t.Errorf("ParseMove(%q).String() = %q, want %q", tt.in, m.String(), tt.out)
+		}
+	}
+
+	for _, bad := range []string{"2-3R", "3-2Rw", "2-2Rw", "0-3Rw", "2-Rw", "-3Rw", "3M", "mw", "2x", "1Rw", "0R"} {
+		if _, err := ParseMove(bad); err == nil {
+			t.Errorf("ParseMove(%q): expected error", bad)
+		}
+	}
+}
+
+// sameAs applies both algs to fresh n×n cubes and reports whether they agree.
+func sameAs(t *testing.T, n int, a, b string) {
+	t.Helper()
+	ca, cb := NewCube(n), NewCube(n)
+	if err := ca.Moves(a); err != nil {
+		t.Fatalf("%dx%d %q: %v", n, n, a, err)
+	}
+	if err := cb.Moves(b); err != nil {
+		t.Fatalf("%dx%d %q: %v", n, n, b, err)
+	}
+	if !sameState(ca, cb) {
+		t.Errorf("%dx%d: %q and %q give different states", n, n, a, b)
+	}
+}
+
+func TestBigCubeMoves(t *testing.T) {
+	sameAs(t, 8, "2R", "Rw R'")
+	sameAs(t, 8, "4U2", "4Uw2 3Uw2")
+	sameAs(t, 8, "2-3Rw", "3Rw R'")
+	sameAs(t, 8, "3r", "3Rw")
+	sameAs(t, 8, "8Rw", "x")
+	sameAs(t, 8, "8R", "L'")
+	sameAs(t, 8, "M", "4-5Lw")
+	sameAs(t, 8, "m", "2-7Lw")
+	sameAs(t, 8, "E'", "4-5Dw'")
+	sameAs(t, 9, "M", "5L")
+	sameAs(t, 9, "S2", "5F2")
+	sameAs(t, 9, "e", "2-8Dw")
+	sameAs(t, 9, "x", "R 2-8Rw L'")
+	sameAs(t, 10, "10Rw", "x")
+	sameAs(t, 10, "10Rw", "3-10Rw Rw")
+	sameAs(t, 11, "x'", "L 2-10Lw R'")
+	sameAs(t, 11, "x'", "M 2-5Lw 7-10l L R'")
+	sameAs(t, 3, "M", "m")
+	sameAs(t, 3, "M", "2L")
+	sameAs(t, 4, "M", "m")
+}
+
+func TestBigCubeScrambles(t *testing.T) {
+	faces := strings.Split(faceLetters, "")
+	suffixes := []string{"", "'", "2"}
+	rng := rand.New(rand.NewSource(5))
+
+	for _, n := range []int{8, 9, 10, 11, 13} {
+		var moves []string
+		for range 200 {
+			face := faces[rng.Intn(len(faces))]
+			lo := 1 + rng.Intn(n)
+			hi := lo + rng.Intn(n-lo+1)
+			var m string
+			switch {
+			case lo == hi:
+				m = face
+				if lo > 1 {
+					m = strconv.Itoa(lo) + face
+				}
+			case lo == 1:
+				m = strconv.Itoa(hi) + face + "w"
+			default:
+				m = strconv.Itoa(lo) + "-" + strconv.Itoa(hi) + strings.ToLower(face)
+			}
+			moves = append(moves, m+suffixes[rng.Intn(len(suffixes))])
+		}
+		moves = append(moves, "M", "E'", "S2", "m'", "e", "s2", "x", "y'", "z2")
+		scramble := strings.Join(moves, " ")
+
+		c := NewCube(n)
+		if err := c.Moves(scramble); err != nil {
+			t.Fatalf("%dx%d: %v", n, n, err)
+		}
+		alg, _ := ParseAlg(scramble)
+		alg.Invert().Apply(c)
+		if !c.IsSolved() {
+			t.Errorf("%dx%d: expected cube to be solved after applying inverse, but it was not", n, n)
+		}
+
+		// a sticker-level check that inverse and rotation algebra agree on big cubes
+		y, _ := ParseMove("y")
+		want := NewCube(n)
+		y.Apply(want)
+		alg.Apply(want)
+		y.Inverse().Apply(want)
+		got := NewCube(n)
+		alg.Rotate(y).Apply(got)
+		if !sameState(got, want) {
+			t.Errorf("%dx%d: rotating a SiGN scramble by y does not match y A y'", n, n)
+		}
+
+		want = mirrorCube(func() *Cube { c := NewCube(n); alg.Apply(c); return c }(), AxisX)
+		got = NewCube(n)
+		alg.Mirror(AxisX).Apply(got)
+		if !sameState(got, want) {
+			t.Errorf("%dx%d: mirroring a SiGN scramble does not match the mirrored state", n, n)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrSyntax is returned (wrapped in a *ParseError) for misplaced brackets,
+// separators and repetition counts.
+var ErrSyntax = errors.New("syntax error")
+
+// Node is a piece of parsed alg notation. Expand flattens it to moves and
+// String prints it back in the notation it was parsed from.
+type Node interface {
+	Expand() Alg
+	String() string
+}
+
+// Sequence is a space separated list of nodes, e.g. R U [R, U].
+type Sequence []Node
+
+// Group is a parenthesised sequence, e.g. (R U R' U').
+type Group struct {
+	Body Sequence
+}
+
+// Commutator is [A, B], expanding to A B A' B'.
+type Commutator struct {
+	A, B Sequence
+}
+
+// Conjugate is [A: B], expanding to A B A'.
+type Conjugate struct {
+	A, B Sequence
+}
+
+// Repeat is a bracketed node followed by a count and/or prime, e.g.
+// (R U R' U')3 or [R, U]'.
+type Repeat struct {
+	Node    Node
+	Count   int
+	Inverse bool
+}
+
+// Expand returns m as a one-move alg.
+func (m Move) Expand() Alg { return Alg{m} }
+
+func (s Sequence) Expand() Alg {
+	var alg Alg
+	for _, n := range s {
+		alg = append(alg, n.Expand()...)
+	}
+	return alg
+}
+
+func (s Sequence) String() string {
+	parts := make([]string, len(s))
+	for i, n := range s {
+		parts[i] = n.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+func (g Group) Expand() Alg         { return g.Body.Expand() }
+func (g Group) String() string      { return "(" + g.Body.String() + ")" }
+func (c Commutator) String() string { return "[" + c.A.String() + ", " + c.B.String() + "]" }
+func (c Conjugate) String() string  { return "[" + c.A.String() + ": " + c.B.String() + "]" }
+
+func (c Commutator) Expand() Alg {
+	a, b := c.A.Expand(), c.B.Expand()
+	alg := append(Alg{}, a...)
+	alg = append(alg, b...)
+	alg = append(alg, a.Invert()...)
+	return append(alg, b.Invert()...)
+}
+
+func (c Conjugate) Expand() Alg {
+	a := c.A.Expand()
+	alg := append(Alg{}, a...)
+	alg = append(alg, c.B.Expand()...)
+	return append(alg, a.Invert()...)
+}
+
+func (r Repeat) Expand() Alg {
+	body := r.Node.Expand()
+	if r.Inverse {
+		body = body.Invert()
+	}
+	var alg Alg
+	for range r.Count {
+		alg = append(alg, body...)
+	}
+	return alg
+}
+
+func (r Repeat) String() string {
+	s := r.Node.String()
+	if r.Count != 1 {
+		s += strconv.Itoa(r.Count)
+	}
+	if r.Inverse {
+		s += "'"
+	}
+	return s
+}
+
+// Limits on repetition, so that a short string cannot expand to an alg
+// too long to hold in memory.
+const (
+	maxRepeat   = 1000  // largest repetition count
+	maxExpanded = 10000 // most moves an alg may expand to
+)
+
+// expandedLen returns the number of moves n expands to, without expanding
+// it, capped at maxExpanded+1.
+func expandedLen(n Node) int {
+	capped := func(l int) int { return min(l, maxExpanded+1) }
+	switch n := n.(type) {
+	case Move:
+		return 1
+	case Sequence:
+		l := 0
+		for _, c := range n {
+			l = capped(l + expandedLen(c))
+		}
+		return l
+	case Group:
+		return expandedLen(n.Body)
+	case Commutator:
+		return capped(2 * (expandedLen(n.A) + expandedLen(n.B)))
+	case Conjugate:
+		return capped(2*expandedLen(n.A) + expandedLen(n.B))
+	case Repeat:
+		return capped(n.Count * expandedLen(n.Node))
+	}
+	return 0
+}
+
+// token is a lexed piece of notation: a move, a bracket, a separator or a
+// repetition suffix.
+type token struct {
+	text   string
+	suffix bool // repetition count/prime directly after a closing bracket
+}
+
+// lexNotation splits s into tokens, dropping whitespace and // comments.
+func lexNotation(s string) []token {
+	var toks []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(s[i:], "//"):
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case strings.IndexByte("()[],:", c) >= 0:
+			toks = append(toks, token{text: s[i : i+1]})
+			i++
+			// a count or prime glued to a closing bracket repeats it
+			if c == ')' || c == ']' {
+				j := i
+				for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '\'') {
+					j++
+				}
+				if j > i {
+					toks = append(toks, token{text: s[i:j], suffix: true})
+					i = j
+				}
+			}
+		default:
+			j := i
+			for j < len(s) && strings.IndexByte(" \t\n\r()[],:", s[j]) < 0 && !strings.HasPrefix(s[j:], "//") {
+				j++
+			}
+			toks = append(toks, token{text: s[i:j]})
+			i = j
+		}
+	}
+	return toks
+}
+
+// notationParser is a recursive-descent parser over lexed tokens.
+type notationParser struct {
+	toks []token
+	pos  int
+}
+
+func (p *notationParser) errorf(err error, format string, args ...any) error {
+	tok := ""
+	if p.pos < len(p.toks) {
+		tok = p.toks[p.pos].text
+	}
+	if format != "" {
+		err = fmt.Errorf("%w: "+format, append([]any{err}, args...)...)
+	}
+	return &ParseError{Index: p.pos, Token: tok, Err: err}
+}
+
+func (p *notationParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos].text
+	}
+	return ""
+}
+
+// sequence parses nodes until a closing bracket, separator or the end.
+func (p *notationParser) sequence() (Sequence, error) {
+	seq, total := Sequence{}, 0
+	for p.pos < len(p.toks) {
+		switch p.peek() {
+		case ")", "]", ",", ":":
+			return seq, nil
+		}
+		start := p.pos
+		n, err := p.node()
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, n)
+		if total += expandedLen(n); total > maxExpanded {
+			p.pos = start
+			return nil, p.errorf(ErrSyntax, "expands to more than %d moves", maxExpanded)
+		}
+	}
+	return seq, nil
+}
+
+// node parses a move or a bracketed node with its repetition suffix.
+func (p *notationParser) node() (Node, error) {
+	tok := p.toks[p.pos]
+	var n Node
+
+	switch tok.text {
+	case "(":
+		p.pos++
+		body, err := p.sequence()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, p.errorf(ErrSyntax, "expected )")
+		}
+		p.pos++
+		n = Group{Body: body}
+
+	case "[":
+		p.pos++
+		a, err := p.sequence()
+		if err != nil {
+			return nil, err
+		}
+		sep := p.peek()
+		if sep != "," && sep != ":" {
+			return nil, p.errorf(ErrSyntax, "expected , or :")
+		}
+		p.pos++
+		b, err := p.sequence()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != "]" {
+			return nil, p.errorf(ErrSyntax, "expected ]")
+		}
+		p.pos++
+		if sep == "," {
+			n = Commutator{A: a, B: b}
+		} else {
+			n = Conjugate{A: a, B: b}
+		}
+
+	default:
+		if tok.suffix {
+			return nil, p.errorf(ErrSyntax, "unexpected repetition")
+		}
+		m, err := ParseMove(tok.text)
+		if err != nil {
+			return nil, p.errorf(err, "")
+		}
+		p.pos++
+		return m, nil
+	}
+
+	if p.pos < len(p.toks) && p.toks[p.pos].suffix {
+		text := p.toks[p.pos].text
+		r := Repeat{Node: n, Count: 1}
+		digits := strings.TrimSuffix(text, "'")
+		r.Inverse = digits != text
+		if digits != "" {
+			count, err := strconv.Atoi(digits)
+			if err != nil || strings.Contains(digits, "'") {
+				return nil, p.errorf(ErrSyntax, "bad repetition")
+			}
+			if count < 1 || count > maxRepeat {
+				return nil, p.errorf(ErrSyntax, "repetition count %d not in 1-%d", count, maxRepeat)
+			}
+			r.Count = count
+		}
+		p.pos++
+		n = r
+	}
+	return n, nil
+}
+
+// ParseNotation parses alg notation with groups, repetitions such as
+// (R U R' U')3, commutators [A, B], conjugates [A: B] and // comments.
+// Brackets nest freely. Repetition counts are 1 to 1000 and the whole
+// alg may expand to at most 10000 moves. Errors are *ParseError values
+// indexing the token stream, where each move, bracket, separator and
+// repetition is one token.
+func ParseNotation(s string) (Sequence, error) {
+	p := &notationParser{toks: lexNotation(s)}
+	seq, err := p.sequence()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, p.errorf(ErrSyntax, "unexpected %s", p.peek())
+	}
+	return seq, nil
+}
@@ -0,0 +1,77 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseNotation(t *testing.T) {
+	tests := []struct {
+		in, pretty, expanded string
+	}{
+		{"R U R' U'", "R U R' U'", "R U R' U'"},
+		{"(R U R' U')3", "(R U R' U')3", "R U R' U' R U R' U' R U R' U'"},
+		{"[R, U]", "[R, U]", "R U R' U'"},
+		{"[R: U]", "[R: U]", "R U R'"},
+		{"[F: [R, U]]", "[F: [R, U]]", "F R U R' U' F'"},
+		{"[R U R', D]2", "[R U R', D]2", "R U R' D R U' R' D' R U R' D R U' R' D'"},
+		{"(R U)' F", "(R U)' F", "U' R' F"},
+		{"[x: (R U)2] // sexy\nU2 // done", "[x: (R U)2] U2", "x R U R U x' U2"},
+		{"(R (U F)2)", "(R (U F)2)", "R U F U F"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		seq, err := ParseNotation(tt.in)
+		if err != nil {
+			t.Errorf("ParseNotation(%q): %v", tt.in, err)
+			continue
+		}
+		if got := seq.String(); got != tt.pretty {
+			t.Errorf("ParseNotation(%q).String() = %q, want %q", tt.in, got, tt.pretty)
+		}
+		if got := seq.Expand().String(); got != tt.expanded {
+			t.Errorf("ParseNotation(%q).Expand() = %q, want %q", tt.in, got, tt.expanded)
+		}
+
+		// the pretty form parses back to the same tree
+		again, err := ParseNotation(seq.String())
+		if err != nil || again.String() != seq.String() {
+			t.Errorf("ParseNotation(%q) does not round-trip: %v", seq.String(), err)
+		}
+	}
+}
+
+func TestParseNotationErrors(t *testing.T) {
+	tests := []struct {
+		in    string
+		index int
+		err   error
+	}{
+		{"(R U", 3, ErrSyntax},
+		{"R U)", 2, ErrSyntax},
+		{"[R U]", 3, ErrSyntax},
+		{"[R, U", 4, ErrSyntax},
+		{"[R, Q]", 3, ErrUnknownMove},
+		{"(R)2'3", 3, ErrSyntax},
+		{"(R)999999999", 3, ErrSyntax},
+		{"U ((R U)1000)10", 1, ErrSyntax},
+		{"[(R)1000, (U)1000]3", 0, ErrSyntax},
+	}
+	for _, tt := range tests {
+		_, err := ParseNotation(tt.in)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Index != tt.index || !errors.Is(err, tt.err) {
+			t.Errorf("ParseNotation(%q) = %v, want token %d: %v", tt.in, err, tt.index+1, tt.err)
+		}
+	}
+}
+
+func TestCubeMovesNotation(t *testing.T) {
+	c := NewCube(3)
+	if err := c.Moves("[R, U]6"); err != nil {
+		t.Fatal(err)
+	}
+	if !c.IsSolved() {
+		t.Error("expected cube to be solved after six sexy moves, but it was not")
+	}
+}
@@ -0,0 +1,104 @@
+package pkg
+
+// rotationColors holds the color on each face of a solved cube after each of
+// CubeRotations, and rotationInverse the index of the rotation undoing it.
+var rotationColors, rotationInverse = func() ([24][6]byte, [24]int) {
+	var colors [24][6]byte
+	for i, rot := range CubeRotations {
+		colors[i] = rotatedColors(rot)
+	}
+	var inverse [24]int
+	for i, rot := range CubeRotations {
+		want := rotatedColors(rot.Invert())
+		for j := range colors {
+			if colors[j] == want {
+				inverse[i] = j
+			}
+		}
+	}
+	return colors, inverse
+}()
+
+// rotatedColors returns the face colors of a solved cube turned by rot.
+func rotatedColors(rot Alg) [6]byte {
+	c := NewCube(2)
+	rot.Apply(c)
+	var colors [6]byte
+	for f := range colors {
+		colors[f] = c.Faces[f][0]
+	}
+	return colors
+}
+
+// faceColors returns the color of every face, or false if some face is not
+// a single color.
+func (c *Cube) faceColors() ([6]byte, bool) {
+	var colors [6]byte
+	for f := range colors {
+		colors[f] = c.Faces[f][0]
+		for _, v := range c.Faces[f] {
+			if v != colors[f] {
+				return colors, false
+			}
+		}
+	}
+	return colors, true
+}
+
+// orientationIndex returns the index in CubeRotations of the rotation that
+// turns a solved cube into c, or -1 if c is not solved in any orientation.
+func (c *Cube) orientationIndex() int {
+	colors, ok := c.faceColors()
+	if !ok {
+		return -1
+	}
+	for i, rc := range rotationColors {
+		if rc == colors {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsSolvedUpToRotation reports whether c is solved in any of the 24
+// orientations.
+func (c *Cube) IsSolvedUpToRotation() bool {
+	return c.orientationIndex() >= 0
+}
+
+// SolvedOrientation reports whether c is solved in some orientation and
+// returns the rotation from CubeRotations that brings it back to the
+// standard one, so that an alg ending in that state can be printed with its
+// ending rotation. The rotation is empty when c is already solved.
+func (c *Cube) SolvedOrientation() (Alg, bool) {
+	i := c.orientationIndex()
+	if i < 0 {
+		return nil, false
+	}
+	return append(Alg{}, CubeRotations[rotationInverse[i]]...), true
+}
+
+// SolvedIn returns a CheckFunc accepting a cube that is solved after any of
+// the given rotations, e.g. SolvedIn(CubeRotations[:4]...) for any y
+// rotation. With no rotations all 24 orientations are accepted.
+func SolvedIn(rots ...Alg) CheckFunc {
+	if len(rots) == 0 {
+		return (*Cube).IsSolvedUpToRotation
+	}
+	accepted := make([][6]byte, len(rots))
+	for i, rot := range rots {
+		accepted[i] = rotatedColors(rot)
+	}
+	return func(c *Cube) bool {
+		colors, ok := c.faceColors()
+		if !ok {
+			return false
+		}
+		for _, a := range accepted {
+			if a == colors {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,60 @@
+package pkg
+
+import "testing"
+
+func TestSolvedOrientation(t *testing.T) {
+	for n := 2; n <= 4; n++ {
+		for _, rot := range CubeRotations {
+			c := NewCube(n)
+			rot.Apply(c)
+			back, ok := c.SolvedOrientation()
+			if !ok {
+				t.Fatalf("%dx%d: %q should be solved up to rotation", n, n, rot)
+			}
+			back.Apply(c)
+			if !c.IsSolved() {
+				t.Errorf("%dx%d: %q followed by %q is not solved", n, n, rot, back)
+			}
+		}
+	}
+
+	c := NewCube(2)
+	c.Moves("R L'")
+	if back, ok := c.SolvedOrientation(); !ok || back.String() != "x'" {
+		t.Errorf("2x2 R L': got %q, %v; want x'", back, ok)
+	}
+	if back, ok := NewCube(3).SolvedOrientation(); !ok || len(back) != 0 {
+		t.Errorf("solved cube: got %q, %v; want an empty rotation", back, ok)
+	}
+
+	c = NewCube(3)
+	c.Moves("R L'")
+	if _, ok := c.SolvedOrientation(); ok || c.IsSolvedUpToRotation() {
+		t.Error("3x3 R L' is not solved in any orientation")
+	}
+}
+
+func TestSolvedIn(t *testing.T) {
+	anyY := SolvedIn(CubeRotations[:4]...)
+	anyRot := SolvedIn()
+	for _, tt := range []struct {
+		alg        string
+		anyY, anyR bool
+	}{
+		{"", true, true},
+		{"y2", true, true},
+		{"U D'", true, true},
+		{"x", false, true},
+		{"z y", false, true},
+		{"R", false, false},
+	} {
+		c := NewCube(2)
+		c.Moves(tt.alg)
+		if got := anyY(c); got != tt.anyY {
+			t.Errorf("%q: any y rotation = %v, want %v", tt.alg, got, tt.anyY)
+		}
+		if got := anyRot(c); got != tt.anyR {
+			t.Errorf("%q: any rotation = %v, want %v", tt.alg, got, tt.anyR)
+		}
+	}
+}
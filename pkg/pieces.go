@@ -0,0 +1,350 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Corner names a corner position or piece, in Kociemba's order.
+type Corner int
+
+const (
+	URF Corner = iota
+	UFL
+	ULB
+	UBR
+	DFR
+	DLF
+	DBL
+	DRB
+)
+
+// Edge names an edge position or piece, in Kociemba's order.
+type Edge int
+
+const (
+	UR Edge = iota
+	UF
+	UL
+	UB
+	DR
+	DF
+	DL
+	DB
+	FR
+	FL
+	BL
+	BR
+)
+
+// cornerFaces lists the faces of each corner clockwise, starting with its
+// U or D face; twist counts how far the U/D color has moved along this list.
+var cornerFaces = [8][3]int{
+	URF: {Uface, Rface, Fface},
+	UFL: {Uface, Fface, Lface},
+	ULB: {Uface, Lface, Bface},
+	UBR: {Uface, Bface, Rface},
+	DFR: {Dface, Fface, Rface},
+	DLF: {Dface, Lface, Fface},
+	DBL: {Dface, Bface, Lface},
+	DRB: {Dface, Rface, Bface},
+}
+
+// edgeFaces lists the faces of each edge, starting with its reference face
+// (U or D, or F or B for the middle layer); flip is 1 when an edge's
+// reference color is not on the position's reference face.
+var edgeFaces = [12][2]int{
+	UR: {Uface, Rface},
+	UF: {Uface, Fface},
+	UL: {Uface, Lface},
+	UB: {Uface, Bface},
+	DR: {Dface, Rface},
+	DF: {Dface, Fface},
+	DL: {Dface, Lface},
+	DB: {Dface, Bface},
+	FR: {Fface, Rface},
+	FL: {Fface, Lface},
+	BL: {Bface, Lface},
+	BR: {Bface, Rface},
+}
+
+func (c Corner) String() string {
+	f := cornerFaces[c]
+	return string([]byte{faceLetters[f[0]], faceLetters[f[1]], faceLetters[f[2]]})
+}
+
+func (e Edge) String() string {
+	f := edgeFaces[e]
+	return string([]byte{faceLetters[f[0]], faceLetters[f[1]]})
+}
+
+// faceSet returns a bitmask of the faces named by letters such as "UFR".
+func faceSet(letters string) (uint8, bool) {
+	var set uint8
+	for i := 0; i < len(letters); i++ {
+		f := strings.IndexByte(faceLetters, letters[i])
+		if f < 0 || set&(1<<f) != 0 {
+			return 0, false
+		}
+		set |= 1 << f
+	}
+	return set, true
+}
+
+// ParseCorner parses a corner name in any letter order, e.g. "UFR" or "URF".
+func ParseCorner(s string) (Corner, error) {
+	if set, ok := faceSet(strings.ToUpper(s)); ok {
+		for c, f := range cornerFaces {
+			if set == 1<<f[0]|1<<f[1]|1<<f[2] {
+				return Corner(c), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid corner %q", s)
+}
+
+// ParseEdge parses an edge name in either letter order, e.g. "UF" or "FU".
+func ParseEdge(s string) (Edge, error) {
+	if set, ok := faceSet(strings.ToUpper(s)); ok {
+		for e, f := range edgeFaces {
+			if set == 1<<f[0]|1<<f[1] {
+				return Edge(e), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid edge %q", s)
+}
+
+// cornerFacelet returns the sticker index on face cornerFaces[pos][k] that
+// belongs to corner pos of an n×n cube.
+func cornerFacelet(n int, pos Corner, k int) int {
+	x, y, z := 0, 0, 0
+	for _, f := range cornerFaces[pos] {
+		switch f {
+		case Rface:
+			x = n - 1
+		case Uface:
+			y = n - 1
+		case Fface:
+			z = n - 1
+		}
+	}
+	return stickerIndex(n, cornerFaces[pos][k], x, y, z)
+}
+
+// edgeFacelet returns the sticker index on face edgeFaces[pos][k] that
+// belongs to the middle edge pos of an odd n×n cube.
+func edgeFacelet(n int, pos Edge, k int) int {
+	x, y, z := n/2, n/2, n/2
+	for _, f := range edgeFaces[pos] {
+		switch f {
+		case Rface:
+			x = n - 1
+		case Lface:
+			x = 0
+		case Uface:
+			y = n - 1
+		case Dface:
+			y = 0
+		case Fface:
+			z = n - 1
+		case Bface:
+			z = 0
+		}
+	}
+	return stickerIndex(n, edgeFaces[pos][k], x, y, z)
+}
+
+// CornerAt returns the corner piece at position pos and its clockwise
+// twist (0-2). ok is false when the stickers there form no corner.
+func (c *Cube) CornerAt(pos Corner) (piece Corner, twist int, ok bool) {
+	var col [3]int
+	for k, f := range cornerFaces[pos] {
+		col[k] = int(c.Faces[f][cornerFacelet(c.Size, pos, k)])
+	}
+	for twist = 0; twist < 3; twist++ {
+		if col[twist] == Uface || col[twist] == Dface {
+			break
+		}
+	}
+	if twist == 3 {
+		return 0, 0, false
+	}
+	for p, f := range cornerFaces {
+		if col[twist] == f[0] && col[(twist+1)%3] == f[1] && col[(twist+2)%3] == f[2] {
+			return Corner(p), twist, true
+		}
+	}
+	return 0, 0, false
+}
+
+// EdgeAt returns the middle edge piece at position pos of an odd cube and
+// its flip (0 or 1). ok is false when the stickers there form no edge.
+func (c *Cube) EdgeAt(pos Edge) (piece Edge, flip int, ok bool) {
+	a := int(c.Faces[edgeFaces[pos][0]][edgeFacelet(c.Size, pos, 0)])
+	b := int(c.Faces[edgeFaces[pos][1]][edgeFacelet(c.Size, pos, 1)])
+	for e, f := range edgeFaces {
+		switch {
+		case a == f[0] && b == f[1]:
+			return Edge(e), 0, true
+		case a == f[1] && b == f[0]:
+			return Edge(e), 1, true
+		}
+	}
+	return 0, 0, false
+}
+
+// CornerOrientation returns the twist of every corner position, or an error
+// if some position holds no valid corner.
+func (c *Cube) CornerOrientation() ([8]byte, error) {
+	var co [8]byte
+	for pos := range co {
+		_, twist, ok := c.CornerAt(Corner(pos))
+		if !ok {
+			return co, fmt.Errorf("no corner at %s", Corner(pos))
+		}
+		co[pos] = byte(twist)
+	}
+	return co, nil
+}
+
+// EdgeOrientation returns the flip of every middle edge position of an odd
+// cube, or an error if some position holds no valid edge.
+func (c *Cube) EdgeOrientation() ([12]byte, error) {
+	var eo [12]byte
+	if c.Size%2 == 0 {
+		return eo, fmt.Errorf("a %dx%d cube has no middle edges", c.Size, c.Size)
+	}
+	for pos := range eo {
+		_, flip, ok := c.EdgeAt(Edge(pos))
+		if !ok {
+			return eo, fmt.Errorf("no edge at %s", Edge(pos))
+		}
+		eo[pos] = byte(flip)
+	}
+	return eo, nil
+}
+
+// Pieces is the cubie-level state of a 2x2 or 3x3: which piece sits in
+// each position and how it is oriented. Edges and centers are only used on
+// the 3x3, where Centers holds the color showing on each face's center.
+type Pieces struct {
+	Size    int
+	CP      [8]Corner
+	CO      [8]byte
+	EP      [12]Edge
+	EO      [12]byte
+	Centers [6]byte
+}
+
+// NewPieces returns the solved piece state of a 2x2 or 3x3.
+func NewPieces(n int) *Pieces {
+	p := &Pieces{Size: n}
+	for i := range p.CP {
+		p.CP[i] = Corner(i)
+	}
+	for i := range p.EP {
+		p.EP[i] = Edge(i)
+	}
+	for f := range p.Centers {
+		p.Centers[f] = byte(f)
+	}
+	return p
+}
+
+// Pieces converts a 2x2 or 3x3 sticker state to pieces. It fails if the
+// cube is another size or some position holds an impossible color set;
+// it does not check that every piece appears exactly once.
+func (c *Cube) Pieces() (*Pieces, error) {
+	if c.Size != 2 && c.Size != 3 {
+		return nil, fmt.Errorf("pieces need a 2x2 or 3x3 cube, got %dx%d", c.Size, c.Size)
+	}
+
+	p := NewPieces(c.Size)
+	for pos := range p.CP {
+		piece, twist, ok := c.CornerAt(Corner(pos))
+		if !ok {
+			return nil, fmt.Errorf("no corner at %s", Corner(pos))
+		}
+		p.CP[pos], p.CO[pos] = piece, byte(twist)
+	}
+
+	if c.Size == 3 {
+		for pos := range p.EP {
+			piece, flip, ok := c.EdgeAt(Edge(pos))
+			if !ok {
+				return nil, fmt.Errorf("no edge at %s", Edge(pos))
+			}
+			p.EP[pos], p.EO[pos] = piece, byte(flip)
+		}
+		for f := range p.Centers {
+			p.Centers[f] = c.Faces[f][4]
+		}
+	}
+
+	return p, nil
+}
+
+// Cube converts the piece state back to stickers.
+func (p *Pieces) Cube() *Cube {
+	c := NewCube(p.Size)
+	n := p.Size
+
+	for pos, piece := range p.CP {
+		for k, f := range cornerFaces[pos] {
+			col := cornerFaces[piece][(k-int(p.CO[pos])+3)%3]
+			c.Faces[f][cornerFacelet(n, Corner(pos), k)] = byte(col)
+		}
+	}
+
+	if n == 3 {
+		for pos, piece := range p.EP {
+			for k, f := range edgeFaces[pos] {
+				col := edgeFaces[piece][(k+int(p.EO[pos]))%2]
+				c.Faces[f][edgeFacelet(n, Edge(pos), k)] = byte(col)
+			}
+		}
+		for f, col := range p.Centers {
+			c.Faces[f][4] = col
+		}
+	}
+
+	return c
+}
+
+// CornerAt returns the corner piece at position pos and its twist.
+func (p *Pieces) CornerAt(pos Corner) (Corner, int) {
+	return p.CP[pos], int(p.CO[pos])
+}
+
+// EdgeAt returns the edge piece at position pos and its flip.
+func (p *Pieces) EdgeAt(pos Edge) (Edge, int) {
+	return p.EP[pos], int(p.EO[pos])
+}
+
+// CornerOrientation returns the twist of every corner position.
+func (p *Pieces) CornerOrientation() [8]byte { return p.CO }
+
+// EdgeOrientation returns the flip of every edge position.
+func (p *Pieces) EdgeOrientation() [12]byte { return p.EO }
+
+// IsSolved reports whether every piece is home and oriented.
+func (p *Pieces) IsSolved() bool {
+	return *p == *NewPieces(p.Size)
+}
+
+// Multiply returns the state reached by doing the moves that made q after
+// those that made p, where both started from solved. Centers are taken from
+// p, so q should not move them.
+func (p *Pieces) Multiply(q *Pieces) *Pieces {
+	r := &Pieces{Size: p.Size, Centers: p.Centers}
+	for i, from := range q.CP {
+		r.CP[i] = p.CP[from]
+		r.CO[i] = (p.CO[from] + q.CO[i]) % 3
+	}
+	for i, from := range q.EP {
+		r.EP[i] = p.EP[from]
+		r.EO[i] = (p.EO[from] + q.EO[i]) % 2
+	}
+	return r
+}
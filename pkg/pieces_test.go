@@ -0,0 +1,110 @@
+package pkg
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPiecesAfterMoves(t *testing.T) {
+	c := NewCube(3)
+	c.Moves("R")
+	if piece, twist, _ := c.CornerAt(URF); piece != DFR || twist != 2 {
+		t.Errorf("after R, URF holds %s twisted %d, want DFR twisted 2", piece, twist)
+	}
+	if piece, flip, _ := c.EdgeAt(UR); piece != FR || flip != 0 {
+		t.Errorf("after R, UR holds %s flipped %d, want FR flipped 0", piece, flip)
+	}
+
+	c = NewCube(3)
+	c.Moves("F")
+	if piece, flip, _ := c.EdgeAt(UF); piece != FL || flip != 1 {
+		t.Errorf("after F, UF holds %s flipped %d, want FL flipped 1", piece, flip)
+	}
+	if eo, _ := c.EdgeOrientation(); eo != [12]byte{UF: 1, DF: 1, FR: 1, FL: 1} {
+		t.Errorf("after F, EdgeOrientation() = %v", eo)
+	}
+
+	c = NewCube(2)
+	c.Moves("R U R' U R U2 R'")
+	co, _ := c.CornerOrientation()
+	if co != [8]byte{URF: 1, UFL: 1, ULB: 1} {
+		t.Errorf("after Sune, CornerOrientation() = %v", co)
+	}
+}
+
+func TestPiecesRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	for _, n := range []int{2, 3} {
+		for range 100 {
+			c := NewCube(n)
+			randomAlg(rng, 30).Apply(c)
+
+			p, err := c.Pieces()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameState(p.Cube(), c) {
+				t.Fatalf("%dx%d: Pieces().Cube() does not match the original state", n, n)
+			}
+
+			twist := 0
+			for _, o := range p.CornerOrientation() {
+				twist += int(o)
+			}
+			if twist%3 != 0 {
+				t.Errorf("%dx%d: corner twist sums to %d", n, n, twist)
+			}
+		}
+	}
+
+	if !NewPieces(3).Cube().IsSolved() {
+		t.Error("expected NewPieces(3).Cube() to be solved")
+	}
+	if _, err := NewCube(4).Pieces(); err == nil {
+		t.Error("expected an error converting a 4x4 to pieces")
+	}
+}
+
+func TestParsePieceNames(t *testing.T) {
+	for _, s := range []string{"UFR", "URF", "rfu"} {
+		if c, err := ParseCorner(s); err != nil || c != URF {
+			t.Errorf("ParseCorner(%q) = %s, %v", s, c, err)
+		}
+	}
+	if e, err := ParseEdge("FU"); err != nil || e != UF {
+		t.Errorf("ParseEdge(\"FU\") = %s, %v", e, err)
+	}
+	for _, s := range []string{"UFD", "UU", "UF", "X"} {
+		if _, err := ParseCorner(s); err == nil {
+			t.Errorf("ParseCorner(%q): expected error", s)
+		}
+	}
+}
+
+func TestPiecesMultiply(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	// face turns only, since other moves turn the centers
+	faceTurns := func() Alg {
+		alg := make(Alg, 20)
+		for i := range alg {
+			alg[i], _ = ParseMove(string(faceLetters[rng.Intn(6)]) + []string{"", "'", "2"}[rng.Intn(3)])
+		}
+		return alg
+	}
+	for _, n := range []int{2, 3} {
+		for range 50 {
+			a, b := faceTurns(), faceTurns()
+			ca, cb, cab := NewCube(n), NewCube(n), NewCube(n)
+			a.Apply(ca)
+			b.Apply(cb)
+			a.Apply(cab)
+			b.Apply(cab)
+			pa, _ := ca.Pieces()
+			pb, _ := cb.Pieces()
+			want, _ := cab.Pieces()
+			if got := pa.Multiply(pb); *got != *want {
+				t.Fatalf("%dx%d %s times %s:\ngot  %+v\nwant %+v", n, n, a, b, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package pkg
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Puzzle is a puzzle state the generic search can explore. Moves are small
+// integers whose meaning is up to the puzzle; P is the puzzle's own pointer
+// type, e.g. Puzzle[*Cube].
+type Puzzle[P any] interface {
+	// Copy returns an independent copy of the state.
+	Copy() P
+	// Apply performs a move in place.
+	Apply(move int)
+	// InverseMove returns the move that undoes move.
+	InverseMove(move int) int
+	// Key returns the state as a string, for use as a map key.
+	Key() string
+	// IsSolved reports whether the puzzle is solved.
+	IsSolved() bool
+	// MoveNotation formats a move.
+	MoveNotation(move int) string
+	// MoveGroup returns a number shared by moves that are redundant when
+	// performed in a row, such as R and R2, which the search never does.
+	MoveGroup(move int) int
+}
+
+// Legality is implemented by puzzles whose legal moves depend on the
+// state, such as bandaged cubes. The search consults CanApply before each
+// move and skips those it rejects.
+type Legality interface {
+	CanApply(move int) bool
+}
+
+var (
+	_ Puzzle[*Cube]   = (*Cube)(nil)
+	_ Puzzle[*Cuboid] = (*Cuboid)(nil)
+)
+
+// moveAmounts lists the amounts a packed move index can hold.
+var moveAmounts = [4]int{1, 2, -1, -2}
+
+// maxIndexLayers is the largest layer count or offset a packed move index
+// can hold; Move.Validate rejects moves past it.
+const maxIndexLayers = 0xff
+
+// Index packs m into a move index for Cube.Apply: 3 bits of face, 3 of
+// kind, 2 of amount and a byte each for the layer count and offset. It
+// panics if m does not fit, such as a turn of 3 quarters or of more than
+// 255 layers, rather than pack a different move; moves that pass
+// Move.Validate always fit.
+func (m Move) Index() int {
+	amount := slices.Index(moveAmounts[:], m.Amount)
+	if amount < 0 || m.Face < 0 || m.Face > 7 || m.Kind < 0 || m.Kind > 7 ||
+		m.Layers < 0 || m.Layers > maxIndexLayers || m.Offset < 0 || m.Offset > maxIndexLayers {
+		panic(fmt.Sprintf("move %+v does not fit in a move index", m))
+	}
+	return m.Face | int(m.Kind)<<3 | amount<<6 | m.Layers<<8 | m.Offset<<16
+}
+
+// MoveFromIndex unpacks a move index made by Move.Index.
+func MoveFromIndex(i int) Move {
+	return Move{
+		Face:   i & 7,
+		Kind:   MoveKind(i >> 3 & 7),
+		Amount: moveAmounts[i>>6&3],
+		Layers: i >> 8 & 0xff,
+		Offset: i >> 16 & 0xff,
+	}
+}
+
+// MoveIndices packs every move of a for the generic search on a Cube. It
+// panics like Move.Index if a move does not fit.
+func MoveIndices(a []Move) []int {
+	idx := make([]int, len(a))
+	for i, m := range a {
+		idx[i] = m.Index()
+	}
+	return idx
+}
+
+// Apply performs the move with the given index, see Move.Index.
+func (c *Cube) Apply(move int) { MoveFromIndex(move).Apply(c) }
+
+// InverseMove returns the index of the inverse move.
+func (c *Cube) InverseMove(move int) int { return MoveFromIndex(move).Inverse().Index() }
+
+// MoveNotation formats a move index in SiGN notation.
+func (c *Cube) MoveNotation(move int) string { return MoveFromIndex(move).String() }
+
+// MoveGroup groups the moves sharing a notation letter, see Move.sameFace.
+func (c *Cube) MoveGroup(move int) int { return moveGroup(MoveFromIndex(move)) }
+
+// moveGroup numbers the classes of Move.sameFace.
+func moveGroup(m Move) int {
+	class := 0
+	switch {
+	case m.isSlice():
+		class = 1
+	case m.IsRotation():
+		class = 2
+	}
+	return m.Face*3 + class
+}
+
+// cuboidMoves is the list of moves registered for cuboids of one size. It
+// only grows, and is replaced rather than modified so that searches can
+// read it without locking.
+type cuboidMoves struct {
+	moves  []Move
+	tables [][]int
+}
+
+// MoveIndex returns the index of m for Cuboid.Apply, registering it on
+// first use, or fails if m is not legal on the cuboid.
+func (q *Cuboid) MoveIndex(m Move) (int, error) {
+	t, err := q.table(m)
+	if err != nil {
+		return 0, err
+	}
+	s := q.shape
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := s.list.Load()
+	if list == nil {
+		list = &cuboidMoves{}
+	}
+	for i, o := range list.moves {
+		if o == m {
+			return i, nil
+		}
+	}
+	s.list.Store(&cuboidMoves{
+		moves:  append(list.moves[:len(list.moves):len(list.moves)], m),
+		tables: append(list.tables[:len(list.tables):len(list.tables)], t),
+	})
+	return len(list.moves), nil
+}
+
+// MoveIndices registers every move of a and returns their indices, failing
+// with a *ParseError on the first illegal move. Inverses are registered too.
+func (q *Cuboid) MoveIndices(a []Move) ([]int, error) {
+	idx := make([]int, len(a))
+	for i, m := range a {
+		var err error
+		if idx[i], err = q.MoveIndex(m); err == nil {
+			_, err = q.MoveIndex(m.Inverse())
+		}
+		if err != nil {
+			return nil, moveError(i, m, err)
+		}
+	}
+	return idx, nil
+}
+
+// ParseMoves parses an alg and registers its moves; see MoveIndices.
+func (q *Cuboid) ParseMoves(s string) ([]int, error) {
+	alg, err := ParseAlg(s)
+	if err != nil {
+		return nil, err
+	}
+	return q.MoveIndices(alg)
+}
+
+// move returns a registered move.
+func (q *Cuboid) move(i int) Move { return q.shape.list.Load().moves[i] }
+
+// Apply performs the registered move with the given index.
+func (q *Cuboid) Apply(move int) { q.applyTable(q.shape.list.Load().tables[move]) }
+
+// InverseMove returns the index of the inverse of a registered move.
+func (q *Cuboid) InverseMove(move int) int {
+	i, err := q.MoveIndex(q.move(move).Inverse())
+	if err != nil {
+		panic(fmt.Sprintf("inverse of legal move %s: %v", q.move(move), err))
+	}
+	return i
+}
+
+// Key returns the stickers as a string.
+func (q *Cuboid) Key() string { return string(q.stickers) }
+
+// MoveNotation formats a registered move.
+func (q *Cuboid) MoveNotation(move int) string { return q.move(move).String() }
+
+// MoveGroup groups registered moves like Cube.MoveGroup.
+func (q *Cuboid) MoveGroup(move int) int { return moveGroup(q.move(move)) }
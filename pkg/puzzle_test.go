@@ -0,0 +1,122 @@
+package pkg
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMoveIndex(t *testing.T) {
+	for _, s := range []string{"R", "U'", "F2", "B2'", "2R", "3Rw'", "2-5Lw2", "12U", "M", "e'", "x2", "y'"} {
+		m, err := ParseMove(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := MoveFromIndex(m.Index()); got != m {
+			t.Errorf("%s: index round trip gave %+v", s, got)
+		}
+	}
+
+	for _, m := range []Move{
+		{Face: Rface, Layers: 1, Amount: 3},
+		{Face: Rface, Layers: 300, Amount: 1},
+		{Face: Rface, Layers: 1, Amount: 1, Offset: 256},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%+v: Index should panic", m)
+				}
+			}()
+			m.Index()
+		}()
+	}
+
+	// moves that would not fit are rejected before they are packed
+	if _, err := ParseMoveSet([]string{"R", "300Rw"}, 300); !errors.Is(err, ErrBadWidth) {
+		t.Errorf("300Rw on a 300x300: got %v, want ErrBadWidth", err)
+	}
+	set, err := ParseMoveSet([]string{"255Rw", "x", "M", "r"}, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	MoveIndices(set)
+}
+
+func TestCubePuzzle(t *testing.T) {
+	c := NewCube(3)
+	moves, _ := ParseAlgStrings([]string{"R", "R'", "R2", "Rw", "M", "x", "U"})
+	idx := MoveIndices(moves)
+	for i, m := range idx {
+		c.Apply(m)
+		c.Apply(c.InverseMove(m))
+		if !c.IsSolved() {
+			t.Errorf("%s and its inverse should cancel", moves[i])
+		}
+		if c.MoveNotation(m) != moves[i].String() {
+			t.Errorf("MoveNotation = %q, want %q", c.MoveNotation(m), moves[i])
+		}
+	}
+	// R, R', R2 and Rw share a group; M, x and U each have their own
+	groups := make(map[int]bool)
+	for _, m := range idx {
+		groups[c.MoveGroup(m)] = true
+	}
+	if len(groups) != 4 {
+		t.Errorf("%d move groups, want 4", len(groups))
+	}
+}
+
+// keyCount counts the distinct states reachable in up to depth moves,
+// through the Puzzle interface only.
+func keyCount[P Puzzle[P]](start P, moves []int, depth int) int {
+	seen := map[string]bool{start.Key(): true}
+	frontier := []P{start}
+	for range depth {
+		var next []P
+		for _, p := range frontier {
+			for _, m := range moves {
+				q := p.Copy()
+				q.Apply(m)
+				if !seen[q.Key()] {
+					seen[q.Key()] = true
+					next = append(next, q)
+				}
+			}
+		}
+		frontier = next
+	}
+	return len(seen)
+}
+
+func TestPuzzleSearch(t *testing.T) {
+	// <R2, U2> on a 2x2x3 and a 3x3 give groups of the same shape
+	q := NewCuboid(2, 3, 2)
+	moves, _ := ParseAlgStrings([]string{"R2", "U2"})
+	qi, err := q.MoveIndices(moves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := keyCount(q, qi, 12); got != 12 {
+		t.Errorf("2x2x3 <R2, U2> has %d states, want 12", got)
+	}
+	if got := keyCount(NewCube(3), MoveIndices(moves), 12); got != 12 {
+		t.Errorf("3x3 <R2, U2> has %d states, want 12", got)
+	}
+
+	c := NewCube(2)
+	c.Moves("R U R' U'")
+	set, _ := ParseAlgStrings([]string{"R", "R'", "U", "U'"})
+	found := FindPuzzleAlgs(c, MoveIndices(set), (*Cube).IsSolved, 4, nil)
+	var got []string
+	for _, sol := range found {
+		var alg Alg
+		for _, m := range sol {
+			alg = append(alg, MoveFromIndex(m))
+		}
+		got = append(got, alg.String())
+	}
+	if !slices.Contains(got, "U R U' R'") {
+		t.Errorf("solutions %v, want U R U' R'", got)
+	}
+}
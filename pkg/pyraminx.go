@@ -0,0 +1,296 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Pyraminx faces, each named after the vertex it does not touch: F is
+// opposite B, L opposite R, R opposite L and D opposite U.
+const (
+	PyraF = iota
+	PyraL
+	PyraR
+	PyraD
+)
+
+// pyraVertices names the vertices turned by U, L, R and B in move order.
+const pyraVertices = "ULRB"
+
+// Pyraminx is a sticker model of the Pyraminx: four faces of nine triangles,
+// each byte holding the face index of its color. Stickers are numbered face
+// by face in the order DisplayColorANSI prints them, row by row.
+//
+// A move index (see Puzzle) is vertex*4 + tip*2 + prime, where vertex is the
+// position of the letter in "ULRB", tip is 1 for the lower-case tip moves u
+// l r b, and prime is 1 for anticlockwise turns. Turns are clockwise looking
+// at the vertex.
+type Pyraminx struct {
+	Stickers [36]byte
+}
+
+var _ Puzzle[*Pyraminx] = (*Pyraminx)(nil)
+
+// pyraTables holds the sticker permutation of each of the 16 moves: the
+// sticker at i moves to pyraTables[m][i].
+var pyraTables = buildPyraTables()
+
+// NewPyraminx returns a solved Pyraminx.
+func NewPyraminx() *Pyraminx {
+	p := &Pyraminx{}
+	for i := range p.Stickers {
+		p.Stickers[i] = byte(i / 9)
+	}
+	return p
+}
+
+// Copy returns a copy of the state.
+func (p *Pyraminx) Copy() *Pyraminx {
+	c := *p
+	return &c
+}
+
+// Face returns the stickers of face f.
+func (p *Pyraminx) Face(f int) []byte { return p.Stickers[9*f : 9*f+9] }
+
+// Apply performs the move with the given index.
+func (p *Pyraminx) Apply(move int) {
+	old := p.Stickers
+	for i, to := range pyraTables[move] {
+		p.Stickers[to] = old[i]
+	}
+}
+
+// InverseMove returns the index of the opposite turn.
+func (p *Pyraminx) InverseMove(move int) int { return move ^ 1 }
+
+// Key returns the stickers as a string.
+func (p *Pyraminx) Key() string { return string(p.Stickers[:]) }
+
+// IsSolved reports whether every face shows a single color. Layer turns
+// carry their tip along, so the tips are solved with the rest.
+func (p *Pyraminx) IsSolved() bool {
+	for i, v := range p.Stickers {
+		if v != p.Stickers[i/9*9] {
+			return false
+		}
+	}
+	return true
+}
+
+// MoveNotation formats a move index, e.g. R, U' or b.
+func (p *Pyraminx) MoveNotation(move int) string {
+	s := string(pyraVertices[move/4])
+	if move&2 != 0 {
+		s = strings.ToLower(s)
+	}
+	if move&1 != 0 {
+		s += "'"
+	}
+	return s
+}
+
+// MoveGroup groups the turns of each vertex, and of each tip, separately.
+func (p *Pyraminx) MoveGroup(move int) int { return move / 2 }
+
+// ParsePyraMove parses a Pyraminx move: U L R B, the tips u l r b, each
+// optionally followed by '.
+func ParsePyraMove(s string) (int, error) {
+	name, prime := strings.CutSuffix(s, "'")
+	if len(name) != 1 {
+		return 0, fmt.Errorf("%w %q", ErrUnknownMove, s)
+	}
+	v := strings.IndexByte(pyraVertices, name[0])
+	tip := 0
+	if v < 0 {
+		v = strings.IndexByte(strings.ToLower(pyraVertices), name[0])
+		tip = 1
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("%w %q", ErrUnknownMove, s)
+	}
+	move := v*4 + tip*2
+	if prime {
+		move++
+	}
+	return move, nil
+}
+
+// ParseMoves parses space separated Pyraminx moves.
+func (p *Pyraminx) ParseMoves(s string) ([]int, error) {
+	var moves []int
+	for i, tok := range strings.Fields(s) {
+		m, err := ParsePyraMove(tok)
+		if err != nil {
+			return nil, &ParseError{Index: i, Token: tok, Err: err}
+		}
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
+
+// Moves parses and applies space separated moves.
+func (p *Pyraminx) Moves(s string) error {
+	moves, err := p.ParseMoves(s)
+	if err != nil {
+		return err
+	}
+	for _, m := range moves {
+		p.Apply(m)
+	}
+	return nil
+}
+
+type vec3 [3]float64
+
+func (a vec3) add(b vec3) vec3      { return vec3{a[0] + b[0], a[1] + b[1], a[2] + b[2]} }
+func (a vec3) sub(b vec3) vec3      { return vec3{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+func (a vec3) scale(k float64) vec3 { return vec3{a[0] * k, a[1] * k, a[2] * k} }
+func (a vec3) dot(b vec3) float64   { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+func (a vec3) cross(b vec3) vec3 {
+	return vec3{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+func (a vec3) norm() vec3 { return a.scale(1 / math.Sqrt(a.dot(a))) }
+
+// rotate turns v by angle (radians, anticlockwise looking down axis from
+// its tip) about the unit vector axis, by Rodrigues' formula.
+func (v vec3) rotate(axis vec3, angle float64) vec3 {
+	c, s := math.Cos(angle), math.Sin(angle)
+	return v.scale(c).add(axis.cross(v).scale(s)).add(axis.scale(axis.dot(v) * (1 - c)))
+}
+
+// triangleStickers returns the centres of the nine stickers of a face with
+// the given apex and left and right vertices as seen from outside, row by
+// row from the apex and left to right.
+func triangleStickers(apex, left, right vec3) [9]vec3 {
+	grid := func(i, k int) vec3 {
+		return apex.add(left.sub(apex).scale(float64(i-k) / 3)).add(right.sub(apex).scale(float64(k) / 3))
+	}
+	var out [9]vec3
+	n := 0
+	for i := range 3 {
+		for j := range 2*i + 1 {
+			k := j / 2
+			var a, b, c vec3
+			if j%2 == 0 {
+				a, b, c = grid(i, k), grid(i+1, k), grid(i+1, k+1)
+			} else {
+				a, b, c = grid(i, k), grid(i, k+1), grid(i+1, k+1)
+			}
+			out[n] = a.add(b).add(c).scale(1.0 / 3)
+			n++
+		}
+	}
+	return out
+}
+
+// pyraStickers returns the centre of every sticker of a Pyraminx with
+// vertices U, L, R and B, laid out as in DisplayColorANSI: F points up from
+// the L-R edge to U; L, R and D point down, with their apexes at L, R and B.
+func pyraStickers() (pts [36]vec3, verts [4]vec3) {
+	r := math.Sqrt(8.0 / 9)
+	u := vec3{0, 1, 0}
+	l := vec3{-r * math.Sqrt(3) / 2, -1.0 / 3, r / 2}
+	rr := vec3{r * math.Sqrt(3) / 2, -1.0 / 3, r / 2}
+	b := vec3{0, -1.0 / 3, -r}
+	faces := [4][9]vec3{
+		PyraF: triangleStickers(u, l, rr),
+		// a face pointing down is read from its bottom apex; its bottom
+		// row is row 0 of triangleStickers, so reverse the rows
+		PyraL: flipRows(triangleStickers(l, b, u)),
+		PyraR: flipRows(triangleStickers(rr, u, b)),
+		PyraD: flipRows(triangleStickers(b, l, rr)),
+	}
+	for f := range faces {
+		copy(pts[9*f:], faces[f][:])
+	}
+	return pts, [4]vec3{u, l, rr, b}
+}
+
+// flipRows reorders the stickers of a face read from its apex so that the
+// row furthest from the apex comes first, keeping left to right order.
+func flipRows(s [9]vec3) [9]vec3 {
+	return [9]vec3{s[4], s[5], s[6], s[7], s[8], s[1], s[2], s[3], s[0]}
+}
+
+// buildPyraTables computes the sticker permutation of every move by
+// turning the stickers near a vertex a third of the way round.
+func buildPyraTables() [16][36]int {
+	pts, verts := pyraStickers()
+	var tables [16][36]int
+	for v, vert := range verts {
+		axis := vert.norm()
+		h := vert.dot(axis)
+		// cuts a third and two thirds of the way from the vertex
+		for tip, cut := range []float64{h / 9, 5 * h / 9} {
+			for prime, angle := range []float64{-2 * math.Pi / 3, 2 * math.Pi / 3} {
+				move := v*4 + tip*2 + prime
+				for i, pt := range pts {
+					tables[move][i] = i
+					if pt.dot(axis) <= cut {
+						continue
+					}
+					to := pt.rotate(axis, angle)
+					for j, q := range pts {
+						if d := to.sub(q); d.dot(d) < 1e-9 {
+							tables[move][i] = j
+						}
+					}
+				}
+			}
+		}
+	}
+	return tables
+}
+
+// pyraColors are the ANSI backgrounds of the faces F, L, R and D.
+var pyraColors = [4]string{ansiBg[Fface], ansiBg[Rface], ansiBg[Bface], ansiBg[Dface]}
+
+// pyraLetters are the color letters of the faces F, L, R and D for Display.
+var pyraLetters = [4]byte{'G', 'R', 'B', 'Y'}
+
+// pyraRow returns the stickers of row r of a face as printed: F has 1, 3
+// and 5 stickers per row, the other faces 5, 3 and 1.
+func (p *Pyraminx) pyraRow(f, r int) []byte {
+	face := p.Face(f)
+	if f == PyraF {
+		return face[r*r : (r+1)*(r+1)]
+	}
+	start := [3]int{0, 5, 8}[r]
+	return face[start : start+5-2*r]
+}
+
+// Display prints the net in ASCII with color letters.
+func (p *Pyraminx) Display() {
+	p.display("  ", func(v byte) string { return string(pyraLetters[v]) + " " })
+}
+
+// DisplayColorANSI prints the net with ANSI-colored stickers: L, F and R
+// side by side, with D below F.
+func (p *Pyraminx) DisplayColorANSI() {
+	p.display(sticker, func(v byte) string { return pyraColors[v] + sticker + reset })
+}
+
+// display prints the net, painting stickers with paint and centring the
+// narrower rows of each face with blank.
+func (p *Pyraminx) display(blank string, paint func(byte) string) {
+	row := func(f, r int) string {
+		cells := p.pyraRow(f, r)
+		pad := strings.Repeat(blank, (5-len(cells))/2)
+		var b strings.Builder
+		b.WriteString(pad)
+		for _, v := range cells {
+			b.WriteString(paint(v))
+		}
+		b.WriteString(pad)
+		return b.String()
+	}
+	for r := range 3 {
+		fmt.Println(row(PyraL, r) + blank + row(PyraF, r) + blank + row(PyraR, r))
+	}
+	indent := strings.Repeat(blank, 6)
+	for r := range 3 {
+		fmt.Println(indent + row(PyraD, r))
+	}
+}
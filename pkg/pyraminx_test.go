@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPyraminxMoves(t *testing.T) {
+	for m := range 16 {
+		p := NewPyraminx()
+		name := p.MoveNotation(m)
+		if got, err := ParsePyraMove(name); err != nil || got != m {
+			t.Errorf("ParsePyraMove(%q) = %d, %v; want %d", name, got, err, m)
+		}
+
+		// layer turns move 12 stickers, tips 3, and three turns are the identity
+		p.Apply(m)
+		moved := 0
+		for i, v := range p.Stickers {
+			if v != byte(i/9) {
+				moved++
+			}
+		}
+		if want := map[bool]int{false: 12, true: 3}[m&2 != 0]; moved != want {
+			t.Errorf("%s moves %d stickers, want %d", name, moved, want)
+		}
+		p.Apply(m)
+		p.Apply(m)
+		if !p.IsSolved() {
+			t.Errorf("three %s turns should solve", name)
+		}
+		p.Apply(m)
+		p.Apply(p.InverseMove(m))
+		if !p.IsSolved() {
+			t.Errorf("%s %s should solve", name, p.MoveNotation(p.InverseMove(m)))
+		}
+	}
+}
+
+func TestPyraminxTips(t *testing.T) {
+	// a tip only touches its own vertex, so it commutes with the other layers
+	for _, s := range []string{"u R u' R'", "l B l' B'", "r U' r' U", "b L b' L'"} {
+		p := NewPyraminx()
+		if err := p.Moves(s); err != nil {
+			t.Fatal(err)
+		}
+		if !p.IsSolved() {
+			t.Errorf("%s should solve", s)
+		}
+	}
+	p := NewPyraminx()
+	p.Moves("u R u' R'")
+	p.Moves("U R U' R'")
+	if p.IsSolved() {
+		t.Error("U R U' R' should not solve")
+	}
+}
+
+func TestPyraminxEdgeCycle(t *testing.T) {
+	// R U R' U' cycles three edges, so six stickers change
+	p := NewPyraminx()
+	p.Moves("R U R' U'")
+	moved := 0
+	for i, v := range p.Stickers {
+		if v != byte(i/9) {
+			moved++
+		}
+	}
+	if moved != 6 {
+		t.Errorf("R U R' U' moves %d stickers, want 6", moved)
+	}
+	sols := FindPuzzleAlgs(p, []int{0, 1, 8, 9}, (*Pyraminx).IsSolved, 4, nil)
+	if len(sols) != 1 || len(sols[0]) != 4 {
+		t.Fatalf("solutions %v, want one of length 4", sols)
+	}
+	for _, m := range sols[0] {
+		p.Apply(m)
+	}
+	if !p.IsSolved() {
+		t.Error("solution should solve")
+	}
+}
+
+func TestPyraminxParseMoves(t *testing.T) {
+	p := NewPyraminx()
+	for _, bad := range []string{"F", "U2", "Rw", "x"} {
+		_, err := p.ParseMoves("R " + bad)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Index != 1 || !errors.Is(err, ErrUnknownMove) {
+			t.Errorf("ParseMoves(R %s) = %v, want ErrUnknownMove at 1", bad, err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package pkg
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Solver finds a solution for a cube state.
+type Solver interface {
+	Solve(c *Cube) (Alg, error)
+}
+
+// NewSolver returns the solver used for random-state scrambles of an n×n
+// cube: a distance table on the 2x2 and the two-phase solver on the 3x3.
+func NewSolver(n int) (Solver, error) {
+	switch n {
+	case 2:
+		return &Solver2x2{}, nil
+	case 3:
+		return &Solver3x3{}, nil
+	}
+	return nil, fmt.Errorf("random-state scrambles need a 2x2 or 3x3, got %dx%d", n, n)
+}
+
+// minScrambleLength is the fewest moves an optimal solution of a scramble
+// may take, as in the WCA regulations: states closer to solved are drawn
+// again.
+var minScrambleLength = map[int]int{2: 4, 3: 2}
+
+// scrambleMoves are the turns that reach every state of an n×n cube held
+// as HeldHome holds it, used to check minScrambleLength.
+var scrambleMoves = map[int]Alg{
+	2: mustParseAlg("U U2 U' R R2 R' F F2 F'"),
+	3: mustParseAlg("U U2 U' R R2 R' F F2 F' D D2 D' L L2 L' B B2 B'"),
+}
+
+func mustParseAlg(s string) Alg {
+	alg, err := ParseAlg(s)
+	if err != nil {
+		panic(err)
+	}
+	return alg
+}
+
+// solvedWithin reports whether c is solved in at most k of moves.
+func solvedWithin(c *Cube, moves Alg, k int) bool {
+	if c.IsSolved() {
+		return true
+	}
+	if k == 0 {
+		return false
+	}
+	for _, m := range moves {
+		r := c.Copy()
+		m.Apply(r)
+		if solvedWithin(r, moves, k-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// HeldHome returns a copy of c turned so that it is held as scrambles
+// leave it: with the DBL corner home on a 2x2 and the centers home on a
+// 3x3. Scrambles never rotate the cube, so ScrambleTo reaches this state,
+// which differs from c only in how the cube is held.
+func HeldHome(c *Cube) (*Cube, error) {
+	home := map[int]func(*Cube) bool{2: dblHome, 3: centersHome}[c.Size]
+	if home == nil {
+		return nil, fmt.Errorf("scrambles need a 2x2 or 3x3, got %dx%d", c.Size, c.Size)
+	}
+	_, r, ok := rotateUntil(c, home)
+	if !ok {
+		return nil, fmt.Errorf("no rotation holds the cube home")
+	}
+	return r, nil
+}
+
+// RandomState returns a uniformly random solvable state of a 2x2 or 3x3.
+// The centers of the 3x3 and the DBL corner of the 2x2 stay home, so
+// every state is counted once.
+func RandomState(n int, rng *rand.Rand) (*Cube, error) {
+	if n != 2 && n != 3 {
+		return nil, fmt.Errorf("random states need a 2x2 or 3x3, got %dx%d", n, n)
+	}
+	p := NewPieces(n)
+
+	if n == 2 {
+		// the seven corners other than DBL, in any order
+		free := []Corner{URF, UFL, ULB, UBR, DFR, DLF, DRB}
+		for i, j := range rng.Perm(len(free)) {
+			p.CP[free[i]] = free[j]
+		}
+		twist := 0
+		for _, pos := range free[:6] {
+			p.CO[pos] = byte(rng.Intn(3))
+			twist += int(p.CO[pos])
+		}
+		p.CO[DRB] = byte((3 - twist%3) % 3)
+		return p.Cube(), nil
+	}
+
+	cp, ep := rng.Perm(8), rng.Perm(12)
+	// corner and edge permutations must have the same parity
+	if permParity(cp) != permParity(ep) {
+		ep[10], ep[11] = ep[11], ep[10]
+	}
+	twist, flip := 0, 0
+	for i := range p.CP {
+		p.CP[i] = Corner(cp[i])
+		if i < 7 {
+			p.CO[i] = byte(rng.Intn(3))
+			twist += int(p.CO[i])
+		}
+	}
+	p.CO[7] = byte((3 - twist%3) % 3)
+	for i := range p.EP {
+		p.EP[i] = Edge(ep[i])
+		if i < 11 {
+			p.EO[i] = byte(rng.Intn(2))
+			flip += int(p.EO[i])
+		}
+	}
+	p.EO[11] = byte(flip % 2)
+	return p.Cube(), nil
+}
+
+// ScrambleTo returns a scramble that takes a solved cube to the state of
+// c as HeldHome holds it: the inverse of the solution s finds, without the
+// rotation it starts with if any, and with half turns written as 2.
+func ScrambleTo(s Solver, c *Cube) (Alg, error) {
+	sol, err := s.Solve(c)
+	if err != nil {
+		return nil, err
+	}
+	for len(sol) > 0 && sol[0].IsRotation() {
+		sol = sol[1:]
+	}
+	return sol.Invert().Simplify(), nil
+}
+
+// RandomScramble returns a random-state scramble of an n×n cube, solving
+// a state from RandomState with s. States an optimal solution takes fewer
+// moves than the WCA minimum to solve are drawn again.
+func RandomScramble(n int, s Solver, rng *rand.Rand) (Alg, error) {
+	for {
+		c, err := RandomState(n, rng)
+		if err != nil {
+			return nil, err
+		}
+		if !solvedWithin(c, scrambleMoves[n], minScrambleLength[n]-1) {
+			return ScrambleTo(s, c)
+		}
+	}
+}
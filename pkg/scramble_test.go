@@ -0,0 +1,78 @@
+package pkg
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRandomScramble(t *testing.T) {
+	rng := rand.New(rand.NewSource(14))
+	s := &Solver3x3{}
+	for range 10 {
+		c, err := RandomState(3, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Validate(); err != nil {
+			t.Fatalf("random state: %v", err)
+		}
+		scramble, err := ScrambleTo(s, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := NewCube(3)
+		scramble.Apply(d)
+		if !d.Equal(c) {
+			t.Errorf("%s does not reach the random state", scramble)
+		}
+	}
+
+	// 2x2 states keep DBL home, so the table needs no rotation
+	for range 100 {
+		c, _ := RandomState(2, rng)
+		if err := c.Validate(); err != nil {
+			t.Fatalf("random 2x2 state: %v", err)
+		}
+		if p, twist, _ := c.CornerAt(DBL); p != DBL || twist != 0 {
+			t.Fatalf("DBL holds %s twisted %d", p, twist)
+		}
+	}
+
+	if _, err := RandomState(4, rng); err == nil {
+		t.Error("4x4: expected error")
+	}
+	if scramble, err := RandomScramble(3, s, rng); err != nil || scramble.Len(HTM) < 2 {
+		t.Errorf("RandomScramble: %s, %v", scramble, err)
+	}
+
+	// a rotated state is scrambled as it is held home, with no rotation
+	c := NewCube(3)
+	c.Moves("x R U")
+	scramble, err := ScrambleTo(s, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	home, _ := HeldHome(c)
+	d := NewCube(3)
+	scramble.Apply(d)
+	for _, m := range scramble {
+		if m.IsRotation() {
+			t.Errorf("%s rotates the cube", scramble)
+		}
+	}
+	if !d.Equal(home) {
+		t.Errorf("%s does not reach x R U held home", scramble)
+	}
+
+	// the minimum counts optimal moves, from any starting rotation
+	c = NewCube(3)
+	c.Moves("y R")
+	if home, _ := HeldHome(c); !solvedWithin(home, scrambleMoves[3], 1) {
+		t.Error("y R should be one move from solved")
+	}
+	c = NewCube(2)
+	c.Moves("R U R' U'")
+	if solvedWithin(c, scrambleMoves[2], 3) {
+		t.Error("R U R' U' should need four moves")
+	}
+}
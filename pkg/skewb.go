@@ -0,0 +1,331 @@
+package pkg
+
+import (
+	"math"
+	"slices"
+	"strings"
+)
+
+// SkewbNotation selects the letters used for Skewb turns.
+type SkewbNotation int
+
+const (
+	// SkewbWCA is the WCA notation: R, L, U and B turn the DRB, DLF, ULB
+	// and DBL corners, so URF never moves.
+	SkewbWCA SkewbNotation = iota
+	// SkewbSarah is Sarah's notation for the top corners: F, R, B and L
+	// turn the top-right corner of the face they name (URF, UBR, ULB, UFL).
+	SkewbSarah
+)
+
+// skewbNotations maps the letters of each notation to the corner they turn.
+var skewbNotations = [2]map[byte]Corner{
+	SkewbWCA:   {'R': DRB, 'L': DLF, 'U': ULB, 'B': DBL},
+	SkewbSarah: {'F': URF, 'R': UBR, 'B': ULB, 'L': UFL},
+}
+
+// Skewb is a sticker model of the Skewb: six faces of five stickers, each
+// byte holding the face index of its color. The faces come in the cube's
+// U R F D L B order, and each face is read like a 3x3 face keeping only
+// the corners and centre: top-left, top-right, centre, bottom-left and
+// bottom-right.
+//
+// A move index (see Puzzle) is corner*2 + prime, where corner is the Corner
+// in the half being turned and prime is 1 for anticlockwise turns. Turns
+// are clockwise looking at the corner. The whole-puzzle rotations x, y and
+// z follow as 16 + axis*2 + prime. Notation only affects how corner turns
+// are parsed and printed.
+type Skewb struct {
+	Stickers [30]byte
+	Notation SkewbNotation
+}
+
+var _ Puzzle[*Skewb] = (*Skewb)(nil)
+
+// skewbFacelets are the 3x3 sticker indices of the five Skewb stickers of
+// a face.
+var skewbFacelets = [5]int{0, 2, 4, 6, 8}
+
+// skewbTables holds the sticker permutation of each move, and
+// skewbRotations that of all 24 whole-puzzle rotations: the sticker at i
+// moves to table[i].
+var skewbTables, skewbRotations = buildSkewbTables()
+
+// NewSkewb returns a solved Skewb using the given notation.
+func NewSkewb(notation SkewbNotation) *Skewb {
+	s := &Skewb{Notation: notation}
+	for i := range s.Stickers {
+		s.Stickers[i] = byte(i / 5)
+	}
+	return s
+}
+
+// Copy returns a copy of the state.
+func (s *Skewb) Copy() *Skewb {
+	c := *s
+	return &c
+}
+
+// Apply performs the move with the given index.
+func (s *Skewb) Apply(move int) { s.permute(&skewbTables[move]) }
+
+func (s *Skewb) permute(table *[30]int) {
+	old := s.Stickers
+	for i, to := range table {
+		s.Stickers[to] = old[i]
+	}
+}
+
+// InverseMove returns the index of the opposite turn.
+func (s *Skewb) InverseMove(move int) int { return move ^ 1 }
+
+// Key returns the stickers as a string.
+func (s *Skewb) Key() string { return string(s.Stickers[:]) }
+
+// IsSolved reports whether every face shows a single color, in any
+// orientation.
+func (s *Skewb) IsSolved() bool {
+	for i, v := range s.Stickers {
+		if v != s.Stickers[i/5*5] {
+			return false
+		}
+	}
+	return true
+}
+
+// MoveNotation formats a move index in the Skewb's notation, e.g. R, B'
+// or y. Corners the notation has no letter for are written as [DFR].
+func (s *Skewb) MoveNotation(move int) string {
+	var name string
+	if move >= 16 {
+		name = string("xyz"[(move-16)/2])
+	} else {
+		corner := Corner(move / 2)
+		name = "[" + corner.String() + "]"
+		for letter, c := range skewbNotations[s.Notation] {
+			if c == corner {
+				name = string(letter)
+			}
+		}
+	}
+	if move&1 != 0 {
+		name += "'"
+	}
+	return name
+}
+
+// TurnNames returns the corner turns of the Skewb's notation, each letter
+// and its prime, in alphabetical order. Rotations are left out.
+func (s *Skewb) TurnNames() []string {
+	var names []string
+	for letter := range skewbNotations[s.Notation] {
+		names = append(names, string(letter), string(letter)+"'")
+	}
+	slices.Sort(names)
+	return names
+}
+
+// MoveGroup groups the turns of each corner, and each rotation axis.
+func (s *Skewb) MoveGroup(move int) int { return move / 2 }
+
+// ParseMoves parses space separated moves in the Skewb's notation, each a
+// letter optionally followed by '. Rotations may also be doubled, as in x2,
+// which gives two quarter rotations.
+func (s *Skewb) ParseMoves(str string) ([]int, error) {
+	var moves []int
+	for i, tok := range strings.Fields(str) {
+		name, prime := strings.CutSuffix(tok, "'")
+		name, double := strings.CutSuffix(name, "2")
+		m := -1
+		if len(name) == 1 {
+			if axis := strings.IndexByte("xyz", name[0]); axis >= 0 {
+				m = 16 + axis*2
+			} else if corner, ok := skewbNotations[s.Notation][name[0]]; ok && !double {
+				m = int(corner) * 2
+			}
+		}
+		if m < 0 || prime && double {
+			return nil, &ParseError{Index: i, Token: tok, Err: ErrUnknownMove}
+		}
+		if prime {
+			m++
+		}
+		moves = append(moves, m)
+		if double {
+			moves = append(moves, m)
+		}
+	}
+	return moves, nil
+}
+
+// Moves parses and applies space separated moves.
+func (s *Skewb) Moves(str string) error {
+	moves, err := s.ParseMoves(str)
+	if err != nil {
+		return err
+	}
+	for _, m := range moves {
+		s.Apply(m)
+	}
+	return nil
+}
+
+// Code packs the state, up to a whole-puzzle rotation, into a number for
+// distance tables: the state is turned so that the URF piece sits solved,
+// then the centres and two stickers of every corner are packed three bits
+// each. The remaining stickers follow from these.
+func (s *Skewb) Code() uint64 {
+	r := *s
+	for i := 1; !r.cornerHome(URF) && i < len(skewbRotations); i++ {
+		r.Stickers = s.Stickers
+		r.permute(&skewbRotations[i])
+	}
+	var code uint64
+	for f := range 5 {
+		code = code<<3 | uint64(r.Stickers[f*5+2])
+	}
+	for c := range Corner(8) {
+		for k := range 2 {
+			code = code<<3 | uint64(r.Stickers[skewbCornerStickers[c][k]])
+		}
+	}
+	return code
+}
+
+// cornerHome reports whether the piece at corner c is solved.
+func (s *Skewb) cornerHome(c Corner) bool {
+	for k, f := range cornerFaces[c] {
+		if s.Stickers[skewbCornerStickers[c][k]] != byte(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// skewbCornerStickers holds the sticker index of every corner on each of
+// its faces, in cornerFaces order.
+var skewbCornerStickers = func() (t [8][3]int) {
+	for c := range Corner(8) {
+		for k, f := range cornerFaces[c] {
+			i := cornerFacelet(3, c, k)
+			for j, v := range skewbFacelets {
+				if v == i {
+					t[c][k] = f*5 + j
+				}
+			}
+		}
+	}
+	return t
+}()
+
+// skewbStickers returns the centre of every sticker in cubie coordinates
+// centred on the middle of the puzzle.
+func skewbStickers() [30]vec3 {
+	var pts [30]vec3
+	for f := range 6 {
+		n := faceNormal[f]
+		for j, i := range skewbFacelets {
+			x, y, z := stickerPos(3, f, i)
+			pts[f*5+j] = vec3{
+				float64(x-1) + float64(n[0])/2,
+				float64(y-1) + float64(n[1])/2,
+				float64(z-1) + float64(n[2])/2,
+			}
+		}
+	}
+	return pts
+}
+
+// skewbPermutation returns where every sticker goes when the stickers
+// with select true are turned by angle about axis.
+func skewbPermutation(pts [30]vec3, axis vec3, angle float64, sel func(vec3) bool) [30]int {
+	var table [30]int
+	for i, pt := range pts {
+		table[i] = i
+		if !sel(pt) {
+			continue
+		}
+		to := pt.rotate(axis, angle)
+		for j, q := range pts {
+			if d := to.sub(q); d.dot(d) < 1e-9 {
+				table[i] = j
+			}
+		}
+	}
+	return table
+}
+
+// buildSkewbTables computes the sticker permutation of every corner turn
+// by turning the half of the puzzle on the corner's side, of the x, y and
+// z rotations, and of all 24 rotations by composing x and y.
+func buildSkewbTables() (turns [22][30]int, rotations [24][30]int) {
+	pts := skewbStickers()
+	all := func(vec3) bool { return true }
+	for c := range Corner(8) {
+		var axis vec3
+		for _, f := range cornerFaces[c] {
+			for a, d := range faceNormal[f] {
+				axis[a] += float64(d)
+			}
+		}
+		axis = axis.norm()
+		half := func(p vec3) bool { return p.dot(axis) > 0 }
+		turns[2*c] = skewbPermutation(pts, axis, -2*math.Pi/3, half)
+		turns[2*c+1] = skewbPermutation(pts, axis, 2*math.Pi/3, half)
+	}
+
+	for a, axis := range []vec3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+		turns[16+2*a] = skewbPermutation(pts, axis, -math.Pi/2, all)
+		turns[16+2*a+1] = skewbPermutation(pts, axis, math.Pi/2, all)
+	}
+
+	x, y := turns[16], turns[18]
+	for i := range rotations[0] {
+		rotations[0][i] = i
+	}
+	seen := map[[30]int]bool{rotations[0]: true}
+	for n, next := 0, 1; n < next; n++ {
+		for _, g := range [][30]int{x, y} {
+			var r [30]int
+			for i, to := range rotations[n] {
+				r[i] = g[to]
+			}
+			if !seen[r] {
+				seen[r] = true
+				rotations[next] = r
+				next++
+			}
+		}
+	}
+	return turns, rotations
+}
+
+// cube returns the Skewb drawn as a 3x3, with each centre filling the
+// middle cross of its face.
+func (s *Skewb) cube() *Cube {
+	c := NewCube(3)
+	for f := range 6 {
+		for _, i := range []int{1, 3, 5, 7} {
+			c.Faces[f][i] = s.Stickers[f*5+2]
+		}
+		for j, i := range skewbFacelets {
+			c.Faces[f][i] = s.Stickers[f*5+j]
+		}
+	}
+	return c
+}
+
+// Display prints the net like Cube.Display.
+func (s *Skewb) Display() { s.cube().Display() }
+
+// DisplayColorANSI prints the net with ANSI-colored stickers.
+func (s *Skewb) DisplayColorANSI() { s.cube().DisplayColorANSI() }
+
+// String returns the stickers as facelet letters, face by face.
+func (s *Skewb) String() string {
+	b := make([]byte, len(s.Stickers))
+	for i, v := range s.Stickers {
+		b[i] = faceLetters[v]
+	}
+	return string(b)
+}
@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSkewbMoves(t *testing.T) {
+	for m := range 16 {
+		s := NewSkewb(SkewbWCA)
+		s.Apply(m)
+		moved := 0
+		for i, v := range s.Stickers {
+			if v != byte(i/5) {
+				moved++
+			}
+		}
+		if moved != 15 {
+			t.Errorf("%s moves %d stickers, want 15", s.MoveNotation(m), moved)
+		}
+		s.Apply(m)
+		s.Apply(m)
+		if !s.IsSolved() {
+			t.Errorf("three %s turns should solve", s.MoveNotation(m))
+		}
+	}
+
+	// WCA turns keep URF in place
+	s := NewSkewb(SkewbWCA)
+	if err := s.Moves("R L' U B R' U' L B'"); err != nil {
+		t.Fatal(err)
+	}
+	if !s.cornerHome(URF) || s.IsSolved() {
+		t.Errorf("URF home %v, solved %v", s.cornerHome(URF), s.IsSolved())
+	}
+}
+
+func TestSkewbNotation(t *testing.T) {
+	for _, n := range []SkewbNotation{SkewbWCA, SkewbSarah} {
+		s := NewSkewb(n)
+		for letter := range skewbNotations[n] {
+			for _, tok := range []string{string(letter), string(letter) + "'"} {
+				moves, err := s.ParseMoves(tok)
+				if err != nil || len(moves) != 1 || s.MoveNotation(moves[0]) != tok {
+					t.Errorf("notation %d: %s parses to %v, %v", n, tok, moves, err)
+				}
+			}
+		}
+	}
+
+	if got := NewSkewb(SkewbSarah).TurnNames(); !slices.Equal(got, []string{"B", "B'", "F", "F'", "L", "L'", "R", "R'"}) {
+		t.Errorf("Sarah's turns: %v", got)
+	}
+
+	// the same corner in both notations
+	wca, sarah := NewSkewb(SkewbWCA), NewSkewb(SkewbSarah)
+	a, _ := wca.ParseMoves("U")
+	b, _ := sarah.ParseMoves("B")
+	if !slices.Equal(a, b) {
+		t.Errorf("WCA U = %v, Sarah's B = %v", a, b)
+	}
+	if moves, _ := sarah.ParseMoves("x2 y'"); len(moves) != 3 {
+		t.Errorf("x2 y' parses to %v, want three rotations", moves)
+	}
+
+	for _, bad := range []string{"F", "R2", "x2'", "Rw"} {
+		_, err := wca.ParseMoves("R " + bad)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Index != 1 || !errors.Is(err, ErrUnknownMove) {
+			t.Errorf("ParseMoves(R %s) = %v, want ErrUnknownMove at 1", bad, err)
+		}
+	}
+}
+
+func TestSkewbRotations(t *testing.T) {
+	s := NewSkewb(SkewbSarah)
+	s.Moves("x y' z2")
+	if !s.IsSolved() || s.Stickers == NewSkewb(SkewbSarah).Stickers {
+		t.Error("a rotated Skewb should be solved but not in place")
+	}
+
+	// Code ignores whole-puzzle rotations
+	s = NewSkewb(SkewbSarah)
+	s.Moves("F R' B L'")
+	code := s.Code()
+	for _, rot := range CubeRotations {
+		r := s.Copy()
+		if err := r.Moves(rot.String()); err != nil {
+			t.Fatal(err)
+		}
+		if r.Code() != code {
+			t.Errorf("Code changes under %s", rot)
+		}
+	}
+}
+
+func TestSkewbDistanceTable(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the full Skewb table")
+	}
+	var moves []int
+	for _, c := range []Corner{DRB, DLF, ULB, DBL} {
+		moves = append(moves, 2*int(c), 2*int(c)+1)
+	}
+	table := NewDistanceTable(NewSkewb(SkewbWCA), moves, (*Skewb).Code)
+
+	// the published distance distribution, with God's number 11
+	want := []int{1, 8, 48, 288, 1728, 10248, 59304, 315198, 1225483, 1455856, 81028, 90}
+	if !slices.Equal(table.Counts, want) || table.Len() != 3149280 {
+		t.Fatalf("counts %v (%d states), want %v", table.Counts, table.Len(), want)
+	}
+
+	// solve in Sarah's notation with a table built from WCA turns
+	s := NewSkewb(SkewbSarah)
+	s.Moves("F R' B L F' R B' L' F")
+	sarah, _ := s.ParseMoves("F F' R R' B B' L L'")
+	d, _ := table.Distance(s)
+	sol, ok := table.Solve(s, sarah)
+	if !ok || len(sol) != d || d > 9 {
+		t.Fatalf("solution %v for distance %d", sol, d)
+	}
+	for _, m := range sol {
+		s.Apply(m)
+	}
+	if !s.IsSolved() {
+		t.Error("solution should solve")
+	}
+}
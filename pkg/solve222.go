@@ -0,0 +1,183 @@
+package pkg
+
+import (
+	"fmt"
+	"sync"
+)
+
+// corners2 is the corner state of a 2x2 for its distance table: moves are
+// the nine turns of U, R and F, which keep DBL in place. A move index is
+// face*3 + turn, with faces in the order of corners2Faces and turns
+// clockwise, half and anticlockwise.
+type corners2 struct {
+	cp [8]Corner
+	co [8]byte
+}
+
+var _ Puzzle[*corners2] = (*corners2)(nil)
+
+// corners2Faces are the faces turned by the 2x2 solver.
+var corners2Faces = [3]byte{'U', 'R', 'F'}
+
+// corners2Turns are the suffixes of the three turns of a face.
+var corners2Turns = [3]string{"", "2", "'"}
+
+// corners2Moves holds the corner state each move makes from solved.
+var corners2Moves = func() (moves [9]Pieces) {
+	for i := range moves {
+		c := NewCube(2)
+		if err := c.Moves((&corners2{}).MoveNotation(i)); err != nil {
+			panic(err)
+		}
+		p, err := c.Pieces()
+		if err != nil {
+			panic(err)
+		}
+		moves[i] = *p
+	}
+	return moves
+}()
+
+func (p *corners2) Copy() *corners2 {
+	c := *p
+	return &c
+}
+
+// Apply performs the move with the given index.
+func (p *corners2) Apply(move int) {
+	m, old := &corners2Moves[move], *p
+	for i, from := range m.CP {
+		p.cp[i] = old.cp[from]
+		p.co[i] = (old.co[from] + m.CO[i]) % 3
+	}
+}
+
+// InverseMove swaps clockwise and anticlockwise turns.
+func (p *corners2) InverseMove(move int) int { return move/3*3 + 2 - move%3 }
+
+// Key returns the state as a string.
+func (p *corners2) Key() string {
+	return fmt.Sprint(p.cp, p.co)
+}
+
+// IsSolved reports whether every corner is home and untwisted.
+func (p *corners2) IsSolved() bool { return p.code() == solvedCorners2().code() }
+
+// MoveNotation formats a move, e.g. R2.
+func (p *corners2) MoveNotation(move int) string {
+	return string(corners2Faces[move/3]) + corners2Turns[move%3]
+}
+
+// MoveGroup groups the turns of each face.
+func (p *corners2) MoveGroup(move int) int { return move / 3 }
+
+// code packs the corners three bits each and their twists two bits each.
+func (p *corners2) code() uint64 {
+	var code uint64
+	for i := range p.cp {
+		code = code<<5 | uint64(p.cp[i])<<2 | uint64(p.co[i])
+	}
+	return code
+}
+
+// solvedCorners2 returns the solved corner state.
+func solvedCorners2() *corners2 {
+	p := &corners2{}
+	for i := range p.cp {
+		p.cp[i] = Corner(i)
+	}
+	return p
+}
+
+// Solver2x2 finds optimal 2x2 solutions in <U,R,F> from a God's-algorithm
+// table of the 3,674,160 states with DBL solved. The table is built on
+// first use.
+type Solver2x2 struct {
+	once  sync.Once
+	table *DistanceTable[*corners2]
+}
+
+// distances returns the distance table, building it if needed.
+func (s *Solver2x2) distances() *DistanceTable[*corners2] {
+	s.once.Do(func() {
+		moves := make([]int, 9)
+		for i := range moves {
+			moves[i] = i
+		}
+		s.table = NewDistanceTable(solvedCorners2(), moves, (*corners2).code)
+	})
+	return s.table
+}
+
+// corners2Of returns the corner state of a 2x2 and the rotation that
+// brings its DBL piece home, which comes first in its solutions.
+func corners2Of(c *Cube) (*corners2, Alg, error) {
+	if c.Size != 2 {
+		return nil, nil, fmt.Errorf("the 2x2 solver needs a 2x2 cube, got %dx%d", c.Size, c.Size)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+	rot, r, ok := rotateUntil(c, dblHome)
+	if !ok {
+		return nil, nil, fmt.Errorf("no rotation brings DBL home")
+	}
+	pieces, err := r.Pieces()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &corners2{cp: pieces.CP, co: pieces.CO}, rot, nil
+}
+
+// Distance returns the number of <U,R,F> moves an optimal solution of c
+// takes.
+func (s *Solver2x2) Distance(c *Cube) (int, error) {
+	p, _, err := corners2Of(c)
+	if err != nil {
+		return 0, err
+	}
+	d, _ := s.distances().Distance(p)
+	return d, nil
+}
+
+// Solve returns an optimal solution of c in <U,R,F>, preceded by a
+// rotation if DBL is not home.
+func (s *Solver2x2) Solve(c *Cube) (Alg, error) {
+	p, rot, err := corners2Of(c)
+	if err != nil {
+		return nil, err
+	}
+	moves := make([]int, 9)
+	for i := range moves {
+		moves[i] = i
+	}
+	sol, ok := s.distances().Solve(p, moves)
+	if !ok {
+		return nil, fmt.Errorf("state not in the 2x2 table")
+	}
+	alg := append(Alg{}, rot...)
+	for _, m := range sol {
+		mv, _ := ParseMove(p.MoveNotation(m))
+		alg = append(alg, mv)
+	}
+	return alg, nil
+}
+
+// dblHome reports whether the DBL corner is home and untwisted.
+func dblHome(c *Cube) bool {
+	piece, twist, _ := c.CornerAt(DBL)
+	return piece == DBL && twist == 0
+}
+
+// rotateUntil tries the whole-cube rotations in order and returns the
+// first, with the rotated copy of c, for which ok holds.
+func rotateUntil(c *Cube, ok func(*Cube) bool) (Alg, *Cube, bool) {
+	for _, rot := range CubeRotations {
+		r := c.Copy()
+		rot.Apply(r)
+		if ok(r) {
+			return rot, r, true
+		}
+	}
+	return nil, nil, false
+}
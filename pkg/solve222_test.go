@@ -0,0 +1,39 @@
+package pkg
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSolver2x2(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the full 2x2 table")
+	}
+	s := &Solver2x2{}
+
+	// the published half-turn distance distribution, with God's number 11
+	want := []int{1, 9, 54, 321, 1847, 9992, 50136, 227536, 870072, 1887748, 623800, 2644}
+	if table := s.distances(); !slices.Equal(table.Counts, want) || table.Len() != 3674160 {
+		t.Fatalf("counts %v (%d states), want %v", table.Counts, table.Len(), want)
+	}
+
+	rng := rand.New(rand.NewSource(12))
+	for range 20 {
+		c := NewCube(2)
+		scramble := randomAlg(rng, 15)
+		scramble.Apply(c)
+		sol, err := s.Solve(c)
+		if err != nil {
+			t.Fatalf("%s: %v", scramble, err)
+		}
+		d, _ := s.Distance(c)
+		if sol.Len(HTM) != d || d > 11 {
+			t.Errorf("%s: solution %s for distance %d", scramble, sol, d)
+		}
+		sol.Apply(c)
+		if !c.IsSolved() {
+			t.Errorf("%s: %s does not solve it", scramble, sol)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Square1 is a Square-1 state: 24 units of 30°, 0-11 in the top layer
+// clockwise seen from above and 12-23 in the bottom layer clockwise seen
+// from below, plus whether the right half of the middle layer is flipped.
+//
+// Each unit holds the solved position of the piece part it shows, so an
+// edge fills one unit and a corner two consecutive ones. The slice runs
+// between units 11|0 and 5|6 of both layers, and / swaps units 0-5 of the
+// top with units 0-5 of the bottom. It is blocked while a corner straddles
+// the slice, so legal moves depend on the shape.
+type Square1 struct {
+	Units         [24]byte
+	MiddleFlipped bool
+}
+
+// Sq1Move is a Square-1 move: a turn of the top and bottom layers by
+// Top and Bottom units (30°) clockwise seen from each face, written (1,0),
+// or a / slice turn.
+type Sq1Move struct {
+	Top, Bottom int
+	Slash       bool
+}
+
+func (m Sq1Move) String() string {
+	if m.Slash {
+		return "/"
+	}
+	return fmt.Sprintf("(%d,%d)", m.Top, m.Bottom)
+}
+
+// Sq1Alg is a sequence of Square-1 moves.
+type Sq1Alg []Sq1Move
+
+// String formats the moves the way WCA scrambles are written, e.g.
+// "(1,0) / (-1,3) /".
+func (a Sq1Alg) String() string {
+	s := make([]string, len(a))
+	for i, m := range a {
+		s[i] = m.String()
+	}
+	return strings.Join(s, " ")
+}
+
+// Slashes returns the number of / moves, the usual length metric.
+func (a Sq1Alg) Slashes() int {
+	n := 0
+	for _, m := range a {
+		if m.Slash {
+			n++
+		}
+	}
+	return n
+}
+
+// sq1Layout describes each unit of the solved puzzle: whether it is an
+// edge ('e') or the first or second half of a corner ('a', 'b'), and the
+// face color it shows on the side. The top starts with the back-right
+// corner, so (1,0) / is legal from solved, and the bottom with the front
+// edge, so (0,-1) / is.
+var sq1Layout = [24]struct {
+	kind byte
+	side int
+}{
+	{'a', Bface}, {'b', Rface}, {'e', Rface}, {'a', Rface}, {'b', Fface}, {'e', Fface},
+	{'a', Fface}, {'b', Lface}, {'e', Lface}, {'a', Lface}, {'b', Bface}, {'e', Bface},
+	{'e', Fface}, {'a', Fface}, {'b', Rface}, {'e', Rface}, {'a', Rface}, {'b', Bface},
+	{'e', Bface}, {'a', Bface}, {'b', Lface}, {'e', Lface}, {'a', Lface}, {'b', Fface},
+}
+
+// NewSquare1 returns a solved Square-1.
+func NewSquare1() *Square1 {
+	s := &Square1{}
+	for i := range s.Units {
+		s.Units[i] = byte(i)
+	}
+	return s
+}
+
+// Copy returns a copy of the state.
+func (s *Square1) Copy() *Square1 {
+	c := *s
+	return &c
+}
+
+// IsSolved reports whether every piece is home and the middle layer is
+// unflipped.
+func (s *Square1) IsSolved() bool { return *s == *NewSquare1() }
+
+// CanSlash reports whether / is legal: no corner straddles the slice.
+func (s *Square1) CanSlash() bool {
+	kind := func(i int) byte { return sq1Layout[s.Units[i]].kind }
+	for _, base := range []int{0, 12} {
+		if kind(base) == 'b' || kind(base+6) == 'b' {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply performs a move, failing with ErrBadTurn if a / is blocked.
+func (s *Square1) Apply(m Sq1Move) error {
+	if m.Slash {
+		if !s.CanSlash() {
+			return fmt.Errorf("%w: / is blocked by a corner", ErrBadTurn)
+		}
+		for i := range 6 {
+			s.Units[i], s.Units[12+i] = s.Units[12+i], s.Units[i]
+		}
+		s.MiddleFlipped = !s.MiddleFlipped
+		return nil
+	}
+	s.turnLayer(0, m.Top)
+	s.turnLayer(12, m.Bottom)
+	return nil
+}
+
+// turnLayer turns the layer starting at unit base by n units clockwise.
+func (s *Square1) turnLayer(base, n int) {
+	old := s.Units
+	for i := range 12 {
+		s.Units[base+(i+n%12+12)%12] = old[base+i]
+	}
+}
+
+// Moves parses and applies an alg; see ParseSq1Alg. On error the state is
+// left unchanged.
+func (s *Square1) Moves(str string) error {
+	alg, err := ParseSq1Alg(str)
+	if err != nil {
+		return err
+	}
+	t := *s
+	for i, m := range alg {
+		if err := t.Apply(m); err != nil {
+			return moveError(i, m, err)
+		}
+	}
+	*s = t
+	return nil
+}
+
+// ParseSq1Alg parses Square-1 notation: layer turns (a,b), with or without
+// parentheses, and / slice turns, separated by optional spaces, e.g.
+// "(1,0)/(-1,3)/" or "1,0 / -1,3 /". Turns are normalised to -5..6.
+func ParseSq1Alg(s string) (Sq1Alg, error) {
+	var alg Sq1Alg
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '/':
+			alg = append(alg, Sq1Move{Slash: true})
+			i++
+		default:
+			j := i + strings.IndexAny(s[i:]+"/", "/")
+			if c == '(' {
+				j = i + strings.IndexByte(s[i:]+")", ')') + 1
+			}
+			tok := strings.TrimSpace(s[i:min(j, len(s))])
+			m, ok := parseSq1Turn(tok)
+			if !ok {
+				return nil, &ParseError{Index: len(alg), Token: tok, Err: ErrSyntax}
+			}
+			alg = append(alg, m)
+			i = j
+		}
+	}
+	return alg, nil
+}
+
+// parseSq1Turn parses a layer turn "(a,b)" or "a,b".
+func parseSq1Turn(tok string) (Sq1Move, bool) {
+	if t, ok := strings.CutPrefix(tok, "("); ok {
+		if tok, ok = strings.CutSuffix(t, ")"); !ok {
+			return Sq1Move{}, false
+		}
+	}
+	a, b, ok := strings.Cut(tok, ",")
+	if !ok {
+		return Sq1Move{}, false
+	}
+	top, err1 := strconv.Atoi(strings.TrimSpace(a))
+	bottom, err2 := strconv.Atoi(strings.TrimSpace(b))
+	if err1 != nil || err2 != nil {
+		return Sq1Move{}, false
+	}
+	return Sq1Move{Top: sq1Turn(top), Bottom: sq1Turn(bottom)}, true
+}
+
+// sq1Turn normalises a layer turn to -5..6.
+func sq1Turn(n int) int {
+	n = (n%12 + 12) % 12
+	if n > 6 {
+		n -= 12
+	}
+	return n
+}
+
+// Shape returns the shapes of the top and bottom layers, each the
+// clockwise sequence of its corners ('c') and edges ('e') starting where
+// it sorts first: a square layer is "cececece".
+func (s *Square1) Shape() (top, bottom string) {
+	return s.layerShape(0), s.layerShape(12)
+}
+
+func (s *Square1) layerShape(base int) string {
+	var pieces []byte
+	start := 0
+	for sq1Layout[s.Units[base+start]].kind == 'b' {
+		start++
+	}
+	for i := range 12 {
+		switch sq1Layout[s.Units[base+(start+i)%12]].kind {
+		case 'e':
+			pieces = append(pieces, 'e')
+		case 'a':
+			pieces = append(pieces, 'c')
+		}
+	}
+	best := string(pieces)
+	for i := range pieces {
+		if r := string(pieces[i:]) + string(pieces[:i]); r < best {
+			best = r
+		}
+	}
+	return best
+}
+
+// IsCubeShape reports whether both layers are square.
+func (s *Square1) IsCubeShape() bool {
+	top, bottom := s.Shape()
+	return top == "cececece" && bottom == "cececece"
+}
+
+// sq1Colors returns the top or bottom color and the side color of unit i.
+func (s *Square1) sq1Colors(i int) (int, int) {
+	u := s.Units[i]
+	ud := Uface
+	if u >= 12 {
+		ud = Dface
+	}
+	return ud, sq1Layout[u].side
+}
+
+// Display prints both layers in ASCII; see display.
+func (s *Square1) Display() {
+	s.display(" ", func(f int) string { return string(faceLetters[f]) + " " })
+}
+
+// DisplayColorANSI prints both layers with ANSI-colored stickers; see
+// display.
+func (s *Square1) DisplayColorANSI() {
+	s.display(sticker, func(f int) string { return ansiBg[f] + sticker + reset })
+}
+
+// display prints each layer as two rows of its 12 units, the top or bottom
+// color above the side color, with a gap where the slice cuts and a bar
+// between the halves of each corner. The middle layer is noted between.
+func (s *Square1) display(gap string, paint func(int) string) {
+	layer := func(name string, base int) {
+		var ud, side strings.Builder
+		for i := range 12 {
+			if i == 6 {
+				ud.WriteString(gap)
+				side.WriteString(gap)
+			}
+			a, b := s.sq1Colors(base + i)
+			ud.WriteString(paint(a))
+			side.WriteString(paint(b))
+		}
+		var halves strings.Builder
+		for i := range 12 {
+			if i == 6 {
+				halves.WriteString(gap)
+			}
+			mark := "  "
+			if sq1Layout[s.Units[base+i]].kind == 'a' {
+				mark = "└─"
+			} else if sq1Layout[s.Units[base+i]].kind == 'b' {
+				mark = "┘ "
+			}
+			halves.WriteString(mark)
+		}
+		fmt.Printf("%-7s %s\n%-7s %s\n%-7s %s\n", name, ud.String(), "", side.String(), "", halves.String())
+	}
+	layer("Top", 0)
+	if s.MiddleFlipped {
+		fmt.Println("Middle  flipped")
+	} else {
+		fmt.Println("Middle  solved")
+	}
+	layer("Bottom", 12)
+}
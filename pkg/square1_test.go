@@ -0,0 +1,78 @@
+package pkg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSquare1Slash(t *testing.T) {
+	for _, ok := range []string{"/", "(1,0)/", "(0,-1)/", "(-2,3)/", "(1,0)/(-1,3)/"} {
+		if err := NewSquare1().Moves(ok); err != nil {
+			t.Errorf("%s: %v", ok, err)
+		}
+	}
+	s := NewSquare1()
+	err := s.Moves("(1,0)/ (-1,0)/ (0,1)/")
+	if !errors.Is(err, ErrBadTurn) {
+		t.Fatalf("(0,1)/ after (1,0)/(-1,0)/: got %v, want ErrBadTurn", err)
+	}
+	if !s.IsSolved() {
+		t.Error("a failed Moves should leave the state unchanged")
+	}
+
+	// / twice, or a turn and its inverse, change nothing
+	s.Moves("(1,0) / / (-1,0) (3,-2) (-3,2)")
+	if !s.IsSolved() {
+		t.Error("should be solved")
+	}
+	// a square half turned over no longer fits a square layer
+	s.Moves("/")
+	if top, bottom := s.Shape(); !s.MiddleFlipped || s.IsCubeShape() || top != bottom {
+		t.Errorf("after /: flipped %v, shape %s %s", s.MiddleFlipped, top, bottom)
+	}
+}
+
+func TestParseSq1Alg(t *testing.T) {
+	for _, s := range []string{"(1,0)/(-1,3)/", "1,0/-1,3/", " (1, 0) / (-1, 3) /", "(13,0)/(11,-9)/"} {
+		alg, err := ParseSq1Alg(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+		} else if alg.String() != "(1,0) / (-1,3) /" || alg.Slashes() != 2 {
+			t.Errorf("%q parses to %q", s, alg)
+		}
+	}
+	for _, bad := range []string{"(1,0", "/ x", "(1;0)/", "(1,0)(2"} {
+		var pe *ParseError
+		if _, err := ParseSq1Alg(bad); !errors.As(err, &pe) || !errors.Is(err, ErrSyntax) {
+			t.Errorf("%q: got %v, want ErrSyntax", bad, err)
+		}
+	}
+}
+
+func TestCubeShapes(t *testing.T) {
+	cases := CubeShapes()
+	if len(cases) != 90 {
+		t.Fatalf("%d shapes, want 90", len(cases))
+	}
+	if c := cases[0]; c.Top != "cececece" || c.Bottom != "cececece" || len(c.Solution) != 0 {
+		t.Errorf("first case %s %s %s, want cube shape", c.Top, c.Bottom, c.Solution)
+	}
+	for _, c := range cases {
+		if top, bottom := c.State.Shape(); top != c.Top || bottom != c.Bottom {
+			t.Errorf("state of %s %s has shape %s %s", c.Top, c.Bottom, top, bottom)
+		}
+		s := c.State.Copy()
+		for _, m := range c.Solution {
+			if err := s.Apply(m); err != nil {
+				t.Fatalf("%s %s: %s: %v", c.Top, c.Bottom, c.Solution, err)
+			}
+		}
+		if !s.IsCubeShape() {
+			t.Errorf("%s %s: %s does not reach cube shape", c.Top, c.Bottom, c.Solution)
+		}
+	}
+	// every shape is at most seven slashes from a cube
+	if n := cases[len(cases)-1].Solution.Slashes(); n != 7 {
+		t.Errorf("longest solution %d slashes, want 7", n)
+	}
+}
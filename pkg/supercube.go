@@ -0,0 +1,194 @@
+package pkg
+
+import "sync"
+
+// faceUp is the direction of row 0 of each face in the net used by
+// Display, in cubie coordinates. A sticker in its home orientation has its
+// top pointing this way.
+var faceUp = [6][3]int{
+	Uface: {0, 0, -1},
+	Dface: {0, 0, 1},
+	Rface: {0, 1, 0},
+	Lface: {0, 1, 0},
+	Fface: {0, 1, 0},
+	Bface: {0, 1, 0},
+}
+
+func cross3(a, b [3]int) [3]int {
+	return [3]int{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func dot3(a, b [3]int) int { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+
+// turnVec turns v a quarter about the unit axis a, clockwise looking at
+// the face a points out of unless prime.
+func turnVec(v, a [3]int, prime bool) [3]int {
+	c, d := cross3(a, v), dot3(a, v)
+	var out [3]int
+	for i := range out {
+		if prime {
+			out[i] = c[i] + d*a[i]
+		} else {
+			out[i] = -c[i] + d*a[i]
+		}
+	}
+	return out
+}
+
+// stickerTop returns the direction the top of a sticker on face f points
+// when it has made o quarter turns clockwise from home.
+func stickerTop(f int, o byte) [3]int {
+	v := faceUp[f]
+	for range o & 3 {
+		v = turnVec(v, faceNormal[f], false)
+	}
+	return v
+}
+
+// stickerTurn is what one quarter turn does to tracked stickers: the
+// sticker at position from[k] moves to to[k] and turns twist[k] quarters
+// clockwise. Positions are face*n*n + index, as in Cube.Stickers.
+type stickerTurn struct {
+	from, to []int32
+	twist    []byte
+}
+
+// stickerTurns holds the turns of an n×n cube by face, direction
+// (clockwise, anticlockwise) and width-1.
+type stickerTurns [6][2][]stickerTurn
+
+var (
+	stickerTurnsMu     sync.Mutex
+	stickerTurnsBySize = map[int]*stickerTurns{}
+)
+
+// stickerTurnsFor returns the turn tables of an n×n cube, building them on
+// first use.
+func stickerTurnsFor(n int) *stickerTurns {
+	stickerTurnsMu.Lock()
+	defer stickerTurnsMu.Unlock()
+	if t, ok := stickerTurnsBySize[n]; ok {
+		return t
+	}
+	t := &stickerTurns{}
+	for face := range 6 {
+		for dir, prime := range []bool{false, true} {
+			for width := 1; width <= n; width++ {
+				t[face][dir] = append(t[face][dir], newStickerTurn(n, face, width, prime))
+			}
+		}
+	}
+	stickerTurnsBySize[n] = t
+	return t
+}
+
+// newStickerTurn works out a turn of the width layers next to face from
+// the geometry: each sticker's position (doubled to stay on the integers
+// about the centre), face and top are turned about the face's axis.
+func newStickerTurn(n, face, width int, prime bool) stickerTurn {
+	var t stickerTurn
+	axis := faceNormal[face]
+	for f := range 6 {
+		for i := range n * n {
+			x, y, z := stickerPos(n, f, i)
+			if cubieLayer(n, face, x, y, z) > width {
+				continue
+			}
+			pos := turnVec([3]int{2*x - n + 1, 2*y - n + 1, 2*z - n + 1}, axis, prime)
+			normal := turnVec(faceNormal[f], axis, prime)
+			top := turnVec(faceUp[f], axis, prime)
+			to := 0
+			for g, v := range faceNormal {
+				if v == normal {
+					to = g
+				}
+			}
+			j := stickerIndex(n, to, (pos[0]+n-1)/2, (pos[1]+n-1)/2, (pos[2]+n-1)/2)
+			for o := range byte(4) {
+				if stickerTop(to, o) == top {
+					t.twist = append(t.twist, o)
+				}
+			}
+			t.from = append(t.from, int32(f*n*n+i))
+			t.to = append(t.to, int32(to*n*n+j))
+		}
+	}
+	return t
+}
+
+// TrackOrientation starts tracking every sticker, as on a supercube or
+// picture cube: where it started and how far it has turned, taking the
+// current position and orientation of each as home. Moves then cost more,
+// so searches should only track stickers when their goal needs it; cubes
+// made by NewCube do not.
+func (c *Cube) TrackOrientation() {
+	if c.Stickers != nil {
+		return
+	}
+	n := c.Size
+	c.Stickers = make([]int32, 6*n*n)
+	for i := range c.Stickers {
+		c.Stickers[i] = int32(i) << 2
+	}
+	c.stickerTurns = stickerTurnsFor(n)
+	c.stickerBuf = make([]int32, 6*n*n)
+}
+
+// Sticker returns the position, as face*n*n + index, the sticker at
+// index i of face f started in, and the quarter turns it has made
+// clockwise since. ok is false when stickers are not tracked.
+func (c *Cube) Sticker(f, i int) (home, turns int, ok bool) {
+	if c.Stickers == nil {
+		return 0, 0, false
+	}
+	v := c.Stickers[f*c.Size*c.Size+i]
+	return int(v >> 2), int(v & 3), true
+}
+
+// turnOrient moves and turns the tracked stickers for a turn of the width
+// layers next to face.
+func (c *Cube) turnOrient(face, width int, prime bool) {
+	dir := 0
+	if prime {
+		dir = 1
+	}
+	t := &c.stickerTurns[face][dir][width-1]
+	buf := c.stickerBuf[:len(t.from)]
+	for k, from := range t.from {
+		buf[k] = c.Stickers[from]
+	}
+	for k, to := range t.to {
+		v := buf[k]
+		c.Stickers[to] = v&^3 | (v+int32(t.twist[k]))&3
+	}
+}
+
+// CentersOriented reports whether every center position holds its own
+// sticker in its home orientation. On big cubes this also rules out
+// same-colored center stickers that have swapped places. It is true when
+// stickers are not tracked.
+func (c *Cube) CentersOriented() bool {
+	if c.Stickers == nil {
+		return true
+	}
+	n := c.Size
+	for f := range 6 {
+		for r := 1; r < n-1; r++ {
+			for col := 1; col < n-1; col++ {
+				i := f*n*n + r*n + col
+				if c.Stickers[i] != int32(i)<<2 {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// IsSolvedOriented reports whether the cube is solved with every center
+// sticker home and upright, as a supercube must be. Corner and edge
+// stickers need no check: their colors tell them apart, so a solved piece
+// is always home and oriented.
+func (c *Cube) IsSolvedOriented() bool {
+	return c.IsSolved() && c.CentersOriented()
+}
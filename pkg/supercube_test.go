@@ -0,0 +1,78 @@
+package pkg
+
+import "testing"
+
+func TestTrackOrientation(t *testing.T) {
+	// every alg returns the stickers home; only some return the centers
+	for _, tc := range []struct {
+		n        int
+		alg      string
+		oriented bool
+	}{
+		{3, "", true},
+		{3, "U2 U2", true},
+		{3, "(R U R' U')6", true}, // each center turns back as often as forward
+		{3, "(R U)105", false},    // R and U have turned 105 times
+		{3, "(x y)3", true},
+		{3, "(M E)6 (M' E')6", true},
+		{4, "Uw2 Uw2 Rw Rw'", true},
+		{4, "(R U)105", false},
+		{4, "[2R' 2B': [2R U' 2R', 2U]]", false}, // cycles three U centers
+		{4, "[2R' 2B': [2R U' 2R', 2U]]3", true},
+	} {
+		c := NewCube(tc.n)
+		c.TrackOrientation()
+		if err := c.Moves(tc.alg); err != nil {
+			t.Fatal(err)
+		}
+		if !c.IsSolved() {
+			t.Fatalf("%dx%d %s should solve the stickers", tc.n, tc.n, tc.alg)
+		}
+		if got := c.IsSolvedOriented(); got != tc.oriented {
+			t.Errorf("%dx%d %s: IsSolvedOriented() = %v, want %v", tc.n, tc.n, tc.alg, got, tc.oriented)
+		}
+	}
+
+	// U turns the U center once and R' the R center three times; x then
+	// turns R and L and carries U to B, pointing the top of the old U
+	// center at R, which is to B's left
+	c := NewCube(3)
+	c.TrackOrientation()
+	c.Moves("U R' x")
+	var centers [6]byte
+	from := [6]int{Uface: Fface, Rface: Rface, Fface: Dface, Dface: Bface, Lface: Lface, Bface: Uface}
+	for f := range centers {
+		home, turns, _ := c.Sticker(f, 4)
+		if home != from[f]*9+4 {
+			t.Errorf("U R' x: face %c holds the %c center", faceLetters[f], faceLetters[home/9])
+		}
+		centers[f] = byte(turns)
+	}
+	if want := [6]byte{Rface: 0, Lface: 3, Bface: 3, Dface: 2}; centers != want {
+		t.Errorf("U R' x: center orientations %v, want %v", centers, want)
+	}
+	if c.Copy().Key() != c.Key() || c.Key() == NewCube(3).Key() {
+		t.Error("copies should keep orientation and keys include it")
+	}
+	check, err := CompileGoal("solved && centers_oriented", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = NewCube(3)
+	c.TrackOrientation()
+	c.Moves("(R U)105")
+	if check(c) || check(NewCube(3)) {
+		t.Error("centers_oriented should fail on (R U)105 and without tracking")
+	}
+	c = NewCube(3)
+	c.TrackOrientation()
+	if !check(c) {
+		t.Error("centers_oriented should pass on a solved tracked cube")
+	}
+	if !GoalTracksStickers("solved && !centers_oriented") || GoalTracksStickers("solved") {
+		t.Error("GoalTracksStickers should find centers_oriented")
+	}
+	if NewCube(3).Stickers != nil {
+		t.Error("NewCube should not track orientation")
+	}
+}
@@ -0,0 +1,460 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Solver3x3 solves the 3x3 with Kociemba's two-phase algorithm: phase 1
+// reaches the subgroup <U,D,R2,F2,L2,B2>, where every corner and edge is
+// oriented and the E-slice edges are in the slice, and phase 2 solves
+// within it. Solutions are short but not optimal. The move and pruning
+// tables are shared by every solver and built on first use.
+type Solver3x3 struct {
+	// MaxLength is the longest solution to accept; 0 means 21. Shorter
+	// limits make Solve slower.
+	MaxLength int
+}
+
+// ErrNoSolution is returned when a solver finds no solution within its
+// limits.
+var ErrNoSolution = errors.New("no solution found")
+
+// The 18 face turns are indexed face*3 + turn, with faces in URFDLB order
+// and turns clockwise, half and anticlockwise.
+var twoPhaseMoves = func() (moves [18]Pieces) {
+	for i := range moves {
+		c := NewCube(3)
+		if err := c.Moves(twoPhaseNotation(i)); err != nil {
+			panic(err)
+		}
+		p, err := c.Pieces()
+		if err != nil {
+			panic(err)
+		}
+		moves[i] = *p
+	}
+	return moves
+}()
+
+// phase2Moves are the indices of the turns that stay in the subgroup.
+var phase2Moves = [10]int{0, 1, 2, 9, 10, 11, 4, 7, 13, 16}
+
+func twoPhaseNotation(move int) string {
+	return string(faceLetters[move/3]) + corners2Turns[move%3]
+}
+
+const (
+	nTwist = 2187  // 3^7 corner twists
+	nFlip  = 2048  // 2^11 edge flips
+	nSlice = 495   // 12 choose 4 places for the slice edges
+	nPerm8 = 40320 // 8! corner or U/D edge permutations
+	nPerm4 = 24    // 4! slice edge permutations
+)
+
+// twoPhaseTables holds the coordinate move tables and pruning tables.
+type twoPhaseTables struct {
+	twist [nTwist][18]uint16
+	flip  [nFlip][18]uint16
+	slice [nSlice][18]uint16
+	cperm [nPerm8][10]uint16
+	eperm [nPerm8][10]uint16
+	sperm [nPerm4][10]uint8
+
+	twistSlice []uint8 // phase 1 distance by twist*nSlice + slice
+	flipSlice  []uint8 // phase 1 distance by flip*nSlice + slice
+	cpermSperm []uint8 // phase 2 distance by cperm*nPerm4 + sperm
+	epermSperm []uint8 // phase 2 distance by eperm*nPerm4 + sperm
+}
+
+var (
+	twoPhaseOnce sync.Once
+	twoPhase     *twoPhaseTables
+)
+
+// tables returns the shared tables, building them if needed.
+func (s *Solver3x3) tables() *twoPhaseTables {
+	twoPhaseOnce.Do(func() { twoPhase = newTwoPhaseTables() })
+	return twoPhase
+}
+
+// twistCoord packs the twists of the first seven corners in base 3.
+func twistCoord(p *Pieces) int {
+	t := 0
+	for _, co := range p.CO[:7] {
+		t = 3*t + int(co)
+	}
+	return t
+}
+
+// flipCoord packs the flips of the first eleven edges in base 2.
+func flipCoord(p *Pieces) int {
+	f := 0
+	for _, eo := range p.EO[:11] {
+		f = 2*f + int(eo)
+	}
+	return f
+}
+
+// sliceCoord ranks the positions of the FR, FL, BL and BR edges among
+// the 495 choices, with 0 when they are home.
+func sliceCoord(p *Pieces) int {
+	s, k := 0, 0
+	for j := BR; j >= UR; j-- {
+		if p.EP[j] >= FR {
+			s += binomial(11-int(j), k+1)
+			k++
+		}
+	}
+	return s
+}
+
+// cpermCoord ranks the corner permutation.
+func cpermCoord(p *Pieces) int {
+	var perm [8]int
+	for i, c := range p.CP {
+		perm[i] = int(c)
+	}
+	return permRank(perm[:])
+}
+
+// epermCoord ranks the permutation of the U and D edges, which must all
+// be in the U and D layers.
+func epermCoord(p *Pieces) int {
+	var perm [8]int
+	for i, e := range p.EP[:8] {
+		perm[i] = int(e)
+	}
+	return permRank(perm[:])
+}
+
+// spermCoord ranks the permutation of the slice edges, which must all be
+// in the slice.
+func spermCoord(p *Pieces) int {
+	var perm [4]int
+	for i, e := range p.EP[8:] {
+		perm[i] = int(e - FR)
+	}
+	return permRank(perm[:])
+}
+
+func binomial(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	r := 1
+	for i := range k {
+		r = r * (n - i) / (i + 1)
+	}
+	return r
+}
+
+// permRank returns the lexicographic rank of a permutation of 0..n-1.
+func permRank(perm []int) int {
+	r := 0
+	for i, v := range perm {
+		smaller := 0
+		for _, w := range perm[i+1:] {
+			if w < v {
+				smaller++
+			}
+		}
+		r = r*(len(perm)-i) + smaller
+	}
+	return r
+}
+
+// permUnrank returns the permutation of 0..n-1 with the given rank.
+func permUnrank(r, n int) []int {
+	digits := make([]int, n)
+	for i := n - 1; i >= 0; i-- {
+		digits[i] = r % (n - i)
+		r /= n - i
+	}
+	left := make([]int, n)
+	for i := range left {
+		left[i] = i
+	}
+	perm := make([]int, n)
+	for i, d := range digits {
+		perm[i] = left[d]
+		left = append(left[:d], left[d+1:]...)
+	}
+	return perm
+}
+
+func newTwoPhaseTables() *twoPhaseTables {
+	t := &twoPhaseTables{}
+
+	for i := range nTwist {
+		p, v := NewPieces(3), i
+		sum := 0
+		for j := 6; j >= 0; j-- {
+			p.CO[j] = byte(v % 3)
+			sum += v % 3
+			v /= 3
+		}
+		p.CO[7] = byte((3 - sum%3) % 3)
+		for m := range twoPhaseMoves {
+			t.twist[i][m] = uint16(twistCoord(p.Multiply(&twoPhaseMoves[m])))
+		}
+	}
+
+	for i := range nFlip {
+		p, v := NewPieces(3), i
+		sum := 0
+		for j := 10; j >= 0; j-- {
+			p.EO[j] = byte(v % 2)
+			sum += v % 2
+			v /= 2
+		}
+		p.EO[11] = byte(sum % 2)
+		for m := range twoPhaseMoves {
+			t.flip[i][m] = uint16(flipCoord(p.Multiply(&twoPhaseMoves[m])))
+		}
+	}
+
+	// every placement of the slice edges, found from the 4-bit subsets
+	for mask := range 1 << 12 {
+		if popcount(mask) != 4 {
+			continue
+		}
+		p := NewPieces(3)
+		slice, other := FR, UR
+		for j := range p.EP {
+			if mask&(1<<j) != 0 {
+				p.EP[j] = slice
+				slice++
+			} else {
+				p.EP[j] = other
+				other++
+			}
+		}
+		i := sliceCoord(p)
+		for m := range twoPhaseMoves {
+			t.slice[i][m] = uint16(sliceCoord(p.Multiply(&twoPhaseMoves[m])))
+		}
+	}
+
+	for i := range nPerm8 {
+		perm := permUnrank(i, 8)
+		cp, ep := NewPieces(3), NewPieces(3)
+		for j, v := range perm {
+			cp.CP[j] = Corner(v)
+			ep.EP[j] = Edge(v)
+		}
+		for k, m := range phase2Moves {
+			t.cperm[i][k] = uint16(cpermCoord(cp.Multiply(&twoPhaseMoves[m])))
+			t.eperm[i][k] = uint16(epermCoord(ep.Multiply(&twoPhaseMoves[m])))
+		}
+	}
+
+	for i := range nPerm4 {
+		p := NewPieces(3)
+		for j, v := range permUnrank(i, 4) {
+			p.EP[8+j] = FR + Edge(v)
+		}
+		for k, m := range phase2Moves {
+			t.sperm[i][k] = uint8(spermCoord(p.Multiply(&twoPhaseMoves[m])))
+		}
+	}
+
+	phase1 := make([]int, 18)
+	for m := range phase1 {
+		phase1[m] = m
+	}
+	phase2 := make([]int, len(phase2Moves))
+	for k := range phase2 {
+		phase2[k] = k
+	}
+	t.twistSlice = pruningTable(nTwist, nSlice, phase1,
+		func(a, m int) int { return int(t.twist[a][m]) },
+		func(b, m int) int { return int(t.slice[b][m]) })
+	t.flipSlice = pruningTable(nFlip, nSlice, phase1,
+		func(a, m int) int { return int(t.flip[a][m]) },
+		func(b, m int) int { return int(t.slice[b][m]) })
+	t.cpermSperm = pruningTable(nPerm8, nPerm4, phase2,
+		func(a, m int) int { return int(t.cperm[a][m]) },
+		func(b, m int) int { return int(t.sperm[b][m]) })
+	t.epermSperm = pruningTable(nPerm8, nPerm4, phase2,
+		func(a, m int) int { return int(t.eperm[a][m]) },
+		func(b, m int) int { return int(t.sperm[b][m]) })
+	return t
+}
+
+func popcount(x int) int {
+	n := 0
+	for ; x != 0; x &= x - 1 {
+		n++
+	}
+	return n
+}
+
+// pruningTable returns the distance from 0 of every pair of coordinates
+// a*nb + b by a breadth-first search with the given move functions.
+func pruningTable(na, nb int, moves []int, moveA, moveB func(int, int) int) []uint8 {
+	dist := make([]uint8, na*nb)
+	for i := range dist {
+		dist[i] = 0xff
+	}
+	dist[0] = 0
+	queue := []int32{0}
+	for len(queue) > 0 {
+		i := int(queue[0])
+		queue = queue[1:]
+		a, b := i/nb, i%nb
+		for _, m := range moves {
+			j := moveA(a, m)*nb + moveB(b, m)
+			if dist[j] == 0xff {
+				dist[j] = dist[i] + 1
+				queue = append(queue, int32(j))
+			}
+		}
+	}
+	return dist
+}
+
+// twoPhaseSearch is the state of one Solve call.
+type twoPhaseSearch struct {
+	t      *twoPhaseTables
+	start  *Pieces
+	max    int
+	moves  []int // phase 1 moves, then phase 2 moves, as 18-move indices
+	result []int
+}
+
+// Solve returns a solution of at most MaxLength moves for a 3x3, preceded
+// by a rotation if its centers are not home, or ErrNoSolution.
+func (s *Solver3x3) Solve(c *Cube) (Alg, error) {
+	if c.Size != 3 {
+		return nil, fmt.Errorf("the 3x3 solver needs a 3x3 cube, got %dx%d", c.Size, c.Size)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	rot, r, ok := rotateUntil(c, centersHome)
+	if !ok {
+		return nil, fmt.Errorf("no rotation brings the centers home")
+	}
+	p, err := r.Pieces()
+	if err != nil {
+		return nil, err
+	}
+
+	max := s.MaxLength
+	if max == 0 {
+		max = 21
+	}
+	search := &twoPhaseSearch{t: s.tables(), start: p, max: max}
+	tw, fl, sl := twistCoord(p), flipCoord(p), sliceCoord(p)
+	for depth := 0; depth <= max; depth++ {
+		if search.phase1(tw, fl, sl, depth) {
+			alg := append(Alg{}, rot...)
+			for _, m := range search.result {
+				mv, _ := ParseMove(twoPhaseNotation(m))
+				alg = append(alg, mv)
+			}
+			return alg, nil
+		}
+	}
+	return nil, ErrNoSolution
+}
+
+// centersHome reports whether every center of a 3x3 shows its own face.
+func centersHome(c *Cube) bool {
+	for f := range 6 {
+		if c.Faces[f][4] != byte(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowed reports whether move may follow the last move searched: it may
+// not turn the same face, or a face before its opposite.
+func (s *twoPhaseSearch) allowed(move int) bool {
+	if len(s.moves) == 0 {
+		return true
+	}
+	f, prev := move/3, s.moves[len(s.moves)-1]/3
+	return f != prev && f != prev-3
+}
+
+// phase1 searches for phase 1 solutions of exactly togo more moves and
+// tries to finish each with phase 2.
+func (s *twoPhaseSearch) phase1(tw, fl, sl, togo int) bool {
+	if togo == 0 {
+		if tw != 0 || fl != 0 || sl != 0 {
+			return false
+		}
+		// a phase 1 solution ending in a phase 2 move has a shorter one
+		if n := len(s.moves); n > 0 {
+			for _, m := range phase2Moves {
+				if s.moves[n-1] == m {
+					return false
+				}
+			}
+		}
+		return s.startPhase2()
+	}
+	t := s.t
+	if int(t.twistSlice[tw*nSlice+sl]) > togo || int(t.flipSlice[fl*nSlice+sl]) > togo {
+		return false
+	}
+	for m := range 18 {
+		if !s.allowed(m) {
+			continue
+		}
+		s.moves = append(s.moves, m)
+		found := s.phase1(int(t.twist[tw][m]), int(t.flip[fl][m]), int(t.slice[sl][m]), togo-1)
+		s.moves = s.moves[:len(s.moves)-1]
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// startPhase2 applies the phase 1 moves and searches for the shortest
+// phase 2 solution that keeps the whole solution within the limit.
+func (s *twoPhaseSearch) startPhase2() bool {
+	p := s.start
+	for _, m := range s.moves {
+		p = p.Multiply(&twoPhaseMoves[m])
+	}
+	cp, ep, sp := cpermCoord(p), epermCoord(p), spermCoord(p)
+	for depth := 0; len(s.moves)+depth <= s.max; depth++ {
+		if s.phase2(cp, ep, sp, depth) {
+			return true
+		}
+	}
+	return false
+}
+
+// phase2 searches for phase 2 solutions of exactly togo more moves.
+func (s *twoPhaseSearch) phase2(cp, ep, sp, togo int) bool {
+	if togo == 0 {
+		if cp == 0 && ep == 0 && sp == 0 {
+			s.result = append([]int(nil), s.moves...)
+			return true
+		}
+		return false
+	}
+	t := s.t
+	if int(t.cpermSperm[cp*nPerm4+sp]) > togo || int(t.epermSperm[ep*nPerm4+sp]) > togo {
+		return false
+	}
+	for k, m := range phase2Moves {
+		if !s.allowed(m) {
+			continue
+		}
+		s.moves = append(s.moves, m)
+		found := s.phase2(int(t.cperm[cp][k]), int(t.eperm[ep][k]), int(t.sperm[sp][k]), togo-1)
+		s.moves = s.moves[:len(s.moves)-1]
+		if found {
+			return true
+		}
+	}
+	return false
+}
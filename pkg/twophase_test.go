@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPermRank(t *testing.T) {
+	for r := range nPerm8 {
+		if got := permRank(permUnrank(r, 8)); got != r {
+			t.Fatalf("permRank(permUnrank(%d)) = %d", r, got)
+		}
+	}
+}
+
+func TestSolver3x3(t *testing.T) {
+	s := &Solver3x3{}
+	rng := rand.New(rand.NewSource(13))
+	for range 20 {
+		c := NewCube(3)
+		scramble := randomAlg(rng, 30)
+		scramble.Apply(c)
+		sol, err := s.Solve(c)
+		if err != nil {
+			t.Fatalf("%s: %v", scramble, err)
+		}
+		if sol.Len(HTM) > 21 {
+			t.Errorf("%s: %s is longer than 21 moves", scramble, sol)
+		}
+		sol.Apply(c)
+		if !c.IsSolved() {
+			t.Errorf("%s: %s does not solve it", scramble, sol)
+		}
+	}
+
+	if sol, err := s.Solve(NewCube(3)); err != nil || len(sol) != 0 {
+		t.Errorf("solved cube: %s, %v", sol, err)
+	}
+	if _, err := s.Solve(NewCube(2)); err == nil {
+		t.Error("2x2: expected error")
+	}
+}
@@ -13,3 +13,24 @@ func Printf(format string, args ...any) {
 	ts := time.Now().Format("2006-01-02 15:04:05")
 	fmt.Printf("[%s] %s", ts, fmt.Sprintf(format, args...))
 }
+
+// ParseMoveSet parses and validates a move set for an n×n cube.
+func ParseMoveSet(moves []string, n int) (Alg, error) {
+	set, err := ParseAlgStrings(moves)
+	if err != nil {
+		return nil, err
+	}
+	if err := set.Validate(n); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// algStrings converts search results back to notation.
+func algStrings(algs []Alg) [][]string {
+	out := make([][]string, len(algs))
+	for i, a := range algs {
+		out[i] = a.Strings()
+	}
+	return out
+}
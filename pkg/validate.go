@@ -0,0 +1,296 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists everything that makes a cube state impossible.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid cube state: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate reports whether the sticker state can be reached from solved
+// by legal moves. It checks that every color appears n² times, that every
+// corner and middle edge exists exactly once, that corner twist and edge
+// flip sum to zero, and on odd cubes that corner, edge and center
+// permutation parities agree. On big cubes every wing must exist exactly
+// once per orbit with the right handedness and every center orbit must
+// hold four stickers of each color. It returns a *ValidationError listing
+// every problem found, or nil.
+func (c *Cube) Validate() error {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	n := c.Size
+	if n < 2 {
+		return &ValidationError{Problems: []string{fmt.Sprintf("size %d is not a cube", n)}}
+	}
+
+	// sticker counts
+	var counts [6]int
+	for f := range 6 {
+		if len(c.Faces[f]) != n*n {
+			add("face %c has %d stickers, want %d", faceLetters[f], len(c.Faces[f]), n*n)
+			return &ValidationError{Problems: problems}
+		}
+		for _, v := range c.Faces[f] {
+			if int(v) >= 6 {
+				add("face %c has unknown color %d", faceLetters[f], v)
+				return &ValidationError{Problems: problems}
+			}
+			counts[v]++
+		}
+	}
+	for col, count := range counts {
+		if count != n*n {
+			add("color %c appears %d times, want %d", faceLetters[col], count, n*n)
+		}
+	}
+
+	// corners
+	cornerOK := true
+	var cp [8]int
+	var seenCorner [8]int
+	twist := 0
+	for pos := range 8 {
+		piece, t, ok := c.CornerAt(Corner(pos))
+		if !ok {
+			add("no valid corner at %s", Corner(pos))
+			cornerOK = false
+			continue
+		}
+		cp[pos] = int(piece)
+		seenCorner[piece]++
+		twist += t
+	}
+	for piece, seen := range seenCorner {
+		if seen != 1 && cornerOK {
+			add("corner %s appears %d times", Corner(piece), seen)
+			cornerOK = false
+		}
+	}
+	if cornerOK && twist%3 != 0 {
+		add("corner twist is off by %d", twist%3)
+	}
+
+	if n%2 == 1 {
+		c.validateMiddle(cp, cornerOK, add)
+	}
+	if n >= 4 {
+		c.validateWings(add)
+		c.validateCenters(add)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateMiddle checks the middle edges and central centers of an odd
+// cube and the parity rule linking them to the corners.
+func (c *Cube) validateMiddle(cp [8]int, cornerOK bool, add func(string, ...any)) {
+	n, mid := c.Size, c.Size/2
+
+	edgeOK := true
+	var ep [12]int
+	var seenEdge [12]int
+	flip := 0
+	for pos := range 12 {
+		piece, f, ok := c.EdgeAt(Edge(pos))
+		if !ok {
+			add("no valid edge at %s", Edge(pos))
+			edgeOK = false
+			continue
+		}
+		ep[pos] = int(piece)
+		seenEdge[piece]++
+		flip += f
+	}
+	for piece, seen := range seenEdge {
+		if seen != 1 && edgeOK {
+			add("edge %s appears %d times", Edge(piece), seen)
+			edgeOK = false
+		}
+	}
+	if edgeOK && flip%2 != 0 {
+		add("a single edge is flipped")
+	}
+
+	// the central centers must be a whole-cube rotation of solved
+	var centers [6]int
+	for f := range 6 {
+		centers[f] = int(c.Faces[f][mid*n+mid])
+	}
+	centerOK := true
+	for f := range 6 {
+		if centers[oppositeFace[f]] != oppositeFace[centers[f]] {
+			add("centers %c and %c are not opposite colors", faceLetters[f], faceLetters[oppositeFace[f]])
+			centerOK = false
+			break
+		}
+	}
+	if centerOK && det3(faceNormal[centers[Uface]], faceNormal[centers[Rface]], faceNormal[centers[Fface]]) !=
+		det3(faceNormal[Uface], faceNormal[Rface], faceNormal[Fface]) {
+		add("centers are a mirror image of the color scheme")
+		centerOK = false
+	}
+
+	if cornerOK && edgeOK && centerOK && permParity(cp[:])^permParity(ep[:])^permParity(centers[:]) != 0 {
+		add("corner, edge and center permutation parities disagree")
+	}
+}
+
+// wingID identifies a wing piece by its home edge and its home position
+// along that edge.
+type wingID struct {
+	edge Edge
+	pos  int
+}
+
+// validateWings checks that every wing orbit of a big cube holds each of
+// its 24 wings exactly once. A wing's handedness is fixed, so a wing
+// flipped in place reads as its partner and is reported as a duplicate.
+func (c *Cube) validateWings(add func(string, ...any)) {
+	n := c.Size
+	for i := 1; i < n-1-i; i++ {
+		seen := make(map[wingID]int)
+		valid := true
+		for e := range 12 {
+			for _, j := range []int{i, n - 1 - i} {
+				id, ok := c.wingAt(Edge(e), j)
+				if !ok {
+					add("no valid wing at %s[%d]", Edge(e), j)
+					valid = false
+					continue
+				}
+				seen[id]++
+			}
+		}
+		if !valid {
+			continue
+		}
+		for e := range 12 {
+			for _, j := range []int{i, n - 1 - i} {
+				if count := seen[wingID{Edge(e), j}]; count != 1 {
+					add("wing %s[%d] appears %d times", Edge(e), j, count)
+				}
+			}
+		}
+	}
+}
+
+// wingCoords returns the cubie coordinates of wing j along edge pos, where
+// j runs along the edge's free axis.
+func wingCoords(n int, pos Edge, j int) [3]int {
+	p := [3]int{j, j, j}
+	for _, f := range edgeFaces[pos] {
+		for axis, v := range faceNormal[f] {
+			switch v {
+			case 1:
+				p[axis] = n - 1
+			case -1:
+				p[axis] = 0
+			}
+		}
+	}
+	return p
+}
+
+// wingAt identifies the wing at position j along edge pos by its colors
+// and handedness.
+func (c *Cube) wingAt(pos Edge, j int) (wingID, bool) {
+	n := c.Size
+	p := wingCoords(n, pos, j)
+	f0, f1 := edgeFaces[pos][0], edgeFaces[pos][1]
+	a := int(c.Faces[f0][stickerIndex(n, f0, p[0], p[1], p[2])])
+	b := int(c.Faces[f1][stickerIndex(n, f1, p[0], p[1], p[2])])
+
+	// offset of the slot from the middle of its edge, doubled to stay integral
+	var d [3]int
+	for axis := range d {
+		if faceNormal[f0][axis] == 0 && faceNormal[f1][axis] == 0 {
+			d[axis] = 2*p[axis] - (n - 1)
+		}
+	}
+	hand := det3(faceNormal[f0], faceNormal[f1], d)
+
+	// colors a and b sit on normals f0 and f1; find the home slot of the
+	// edge with those colors where they have the same handedness
+	for e, home := range edgeFaces {
+		var na, nb int
+		switch {
+		case home[0] == a && home[1] == b:
+			na, nb = home[0], home[1]
+		case home[0] == b && home[1] == a:
+			na, nb = home[1], home[0]
+		default:
+			continue
+		}
+		for _, k := range []int{j, n - 1 - j} {
+			hp := wingCoords(n, Edge(e), k)
+			var hd [3]int
+			for axis := range hd {
+				if faceNormal[na][axis] == 0 && faceNormal[nb][axis] == 0 {
+					hd[axis] = 2*hp[axis] - (n - 1)
+				}
+			}
+			if det3(faceNormal[na], faceNormal[nb], hd) == hand {
+				return wingID{Edge(e), k}, true
+			}
+		}
+	}
+	return wingID{}, false
+}
+
+// validateCenters checks that every center orbit of a big cube holds four
+// stickers of each color.
+func (c *Cube) validateCenters(add func(string, ...any)) {
+	n := c.Size
+	for r := 1; r < n-1; r++ {
+		for col := 1; col < n-1; col++ {
+			// visit each orbit once, from its lowest index
+			cells := [4]int{r*n + col, col*n + (n - 1 - r), (n-1-r)*n + (n - 1 - col), (n-1-col)*n + r}
+			if cells[0] != min(cells[0], cells[1], cells[2], cells[3]) || cells[0] == cells[1] {
+				continue // the central center of an odd cube is checked with the middle edges
+			}
+			var counts [6]int
+			for f := range 6 {
+				for _, i := range cells {
+					counts[c.Faces[f][i]]++
+				}
+			}
+			for color, count := range counts {
+				if count != 4 {
+					add("center orbit (%d,%d) has %d stickers of color %c, want 4", r, col, count, faceLetters[color])
+				}
+			}
+		}
+	}
+}
+
+// permParity returns 0 for an even permutation of 0..len(p)-1 and 1 for an
+// odd one.
+func permParity(p []int) int {
+	parity := 0
+	seen := make([]bool, len(p))
+	for i := range p {
+		if seen[i] {
+			continue
+		}
+		length := 0
+		for j := i; !seen[j]; j = p[j] {
+			seen[j] = true
+			length++
+		}
+		parity ^= (length - 1) & 1
+	}
+	return parity
+}
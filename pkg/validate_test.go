@@ -0,0 +1,99 @@
+package pkg
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestValidateScrambles(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for n := 2; n <= 7; n++ {
+		for range 20 {
+			c := NewCube(n)
+			randomAlg(rng, 40).Apply(c)
+			if n >= 4 {
+				c.Moves("2R 2U' 2F2")
+			}
+			if err := c.Validate(); err != nil {
+				t.Fatalf("%dx%d: scrambled cube reported invalid: %v", n, n, err)
+			}
+		}
+	}
+}
+
+// swapStickers exchanges two stickers given as (face, index) pairs.
+func swapStickers(c *Cube, f1, i1, f2, i2 int) {
+	c.Faces[f1][i1], c.Faces[f2][i2] = c.Faces[f2][i2], c.Faces[f1][i1]
+}
+
+func TestValidateRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		corrupt func(c *Cube)
+		want    string
+	}{
+		{"twisted corner", 3, func(c *Cube) {
+			p := [3]int{}
+			for k := range p {
+				p[k] = cornerFacelet(3, URF, k)
+			}
+			swapStickers(c, Uface, p[0], Rface, p[1])
+			swapStickers(c, Rface, p[1], Fface, p[2])
+		}, "corner twist"},
+		{"twisted 2x2 corner", 2, func(c *Cube) {
+			swapStickers(c, Uface, cornerFacelet(2, URF, 0), Rface, cornerFacelet(2, URF, 1))
+			swapStickers(c, Rface, cornerFacelet(2, URF, 1), Fface, cornerFacelet(2, URF, 2))
+		}, "corner twist"},
+		{"flipped edge", 3, func(c *Cube) {
+			swapStickers(c, Uface, edgeFacelet(3, UF, 0), Fface, edgeFacelet(3, UF, 1))
+		}, "edge is flipped"},
+		{"swapped edges", 3, func(c *Cube) {
+			swapStickers(c, Uface, edgeFacelet(3, UF, 0), Uface, edgeFacelet(3, UR, 0))
+			swapStickers(c, Fface, edgeFacelet(3, UF, 1), Rface, edgeFacelet(3, UR, 1))
+		}, "parities disagree"},
+		{"duplicate sticker", 3, func(c *Cube) {
+			c.Faces[Uface][0] = Dface
+		}, "appears"},
+		{"swapped centers", 3, func(c *Cube) {
+			swapStickers(c, Uface, 4, Fface, 4)
+		}, "not opposite"},
+		{"mirrored centers", 3, func(c *Cube) {
+			swapStickers(c, Rface, 4, Lface, 4)
+		}, "mirror image"},
+		{"flipped wing", 4, func(c *Cube) {
+			p := wingCoords(4, UF, 1)
+			swapStickers(c, Uface, stickerIndex(4, Uface, p[0], p[1], p[2]), Fface, stickerIndex(4, Fface, p[0], p[1], p[2]))
+		}, "wing"},
+		{"flipped 5x5 wing", 5, func(c *Cube) {
+			p := wingCoords(5, BL, 3)
+			swapStickers(c, Bface, stickerIndex(5, Bface, p[0], p[1], p[2]), Lface, stickerIndex(5, Lface, p[0], p[1], p[2]))
+		}, "wing"},
+		{"center orbit", 4, func(c *Cube) {
+			swapStickers(c, Uface, 5, Rface, 0)
+		}, "center orbit"},
+	}
+
+	for _, tt := range tests {
+		c := NewCube(tt.n)
+		tt.corrupt(c)
+		err := c.Validate()
+		var ve *ValidationError
+		if !errors.As(err, &ve) {
+			t.Errorf("%s: Validate() = %v, want a *ValidationError", tt.name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Validate() = %v, want a problem mentioning %q", tt.name, err, tt.want)
+		}
+	}
+
+	// a 2x2 has no permutation parity constraint
+	c := NewCube(2)
+	c.Moves("R U R' U' R' F R2 U' R' U' R U R' F'")
+	if err := c.Validate(); err != nil {
+		t.Errorf("2x2 corner swap reported invalid: %v", err)
+	}
+}